@@ -0,0 +1,286 @@
+package seeauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Taoist-Labs/see-auth-go/autherr"
+	"github.com/Taoist-Labs/see-auth-go/common"
+	"github.com/Taoist-Labs/see-auth-go/proof"
+	"github.com/Taoist-Labs/see-auth-go/signature"
+)
+
+// Authenticator verifies and issues SeeAuth credentials.
+// Each instance owns its trusted attesters, proof lifetime, domain list, replay store, clock and block-number source,
+// so differently configured instances (e.g. staging and production) can run in the same process.
+// The package-level `SeeDAOAuth`, `Auth` and `GenerateNonce` functions use a default instance.
+type Authenticator struct {
+	attesters      proof.AttesterSet
+	proofLifetime  time.Duration
+	allowedDomains []string
+	replayStore    ReplayStore
+	now            func() time.Time
+	blockNumber    common.BlockNumberSource
+	chainID        int64
+	deployments    []proof.Deployment
+	contractCaller signature.ContractCaller
+	wallets        map[WalletName]signature.WalletVerifier
+	namespaces     *signature.NamespaceRegistry
+	messagePolicy  signature.MessagePolicy
+}
+
+// Option configures an Authenticator
+type Option func(*Authenticator)
+
+// WithAttester sets the address of the only trusted attester, proofs signed by other addresses are rejected
+func WithAttester(attester string) Option {
+	return func(a *Authenticator) {
+		a.attesters = proof.AttesterSet{{Address: attester}}
+	}
+}
+
+// WithAttesters sets the trusted attesters, a proof signed by any of them which is valid at the moment is accepted.
+// To rotate the key, add the new attester while the old one is still valid, then let the old one expire by `NotAfter`
+func WithAttesters(attesters ...proof.Attester) Option {
+	return func(a *Authenticator) {
+		a.attesters = attesters
+	}
+}
+
+// WithProofLifetime sets how long an issued proof is valid
+func WithProofLifetime(proofLifetime time.Duration) Option {
+	return func(a *Authenticator) {
+		a.proofLifetime = proofLifetime
+	}
+}
+
+// WithAllowedDomains sets the domains which are allowed to request a signature, e.g. `app.seedao.xyz`, `*.seedao.xyz` or `localhost:3000`.
+// Both the `domain` and the `uri` of the SIWE message must match one of them
+func WithAllowedDomains(domains ...string) Option {
+	return func(a *Authenticator) {
+		a.allowedDomains = domains
+	}
+}
+
+// WithReplayStore sets the store which remembers used proofs,
+// the default is a MemoryReplayStore, which can't protect several replicas.
+// The store is used as it is, a SQLReplayStore used with `WithClock` needs the same clock by `SQLReplayStore.WithClock`
+func WithReplayStore(store ReplayStore) Option {
+	return func(a *Authenticator) {
+		a.replayStore = store
+	}
+}
+
+// WithClock sets the function used to get the current time, it is useful for testing
+func WithClock(now func() time.Time) Option {
+	return func(a *Authenticator) {
+		a.now = now
+	}
+}
+
+// WithBlockNumberSource sets the source of the latest block number, which is used by `GenerateNonce` and the freshness check.
+// The default source is `https://rpc.ankr.com/eth`, see `common.RPCBlockNumberSource` to use your own node,
+// and `common.BlockTracker` to take the RPC off the login hot path
+func WithBlockNumberSource(source common.BlockNumberSource) Option {
+	return func(a *Authenticator) {
+		a.blockNumber = source
+	}
+}
+
+// WithChainID sets the chain of the block-number source, it is put in the nonce by `GenerateNonce`,
+// and a nonce of another chain fails the freshness check. The default is 1, Ethereum mainnet
+func WithChainID(chainID int64) Option {
+	return func(a *Authenticator) {
+		a.chainID = chainID
+	}
+}
+
+// WithDeployments sets the EAS deployments, proofs are issued on the first one, and a proof issued on any of them is accepted.
+// The default is `proof.DefaultDeployments()` at the creation of the Authenticator, i.e. proofs are issued on `proof.DefaultDeployment`.
+// See `proof.LookupDeployment` for the built-in deployments
+func WithDeployments(deployments ...proof.Deployment) Option {
+	return func(a *Authenticator) {
+		a.deployments = deployments
+	}
+}
+
+// WithContractCaller sets the caller used to verify signatures of contract wallets (e.g. Safe) by EIP-1271,
+// and of counterfactual smart accounts by EIP-6492. `*ethclient.Client` of the chain in the SIWE message can be used.
+// Without it, only EOA wallets can sign in
+func WithContractCaller(caller signature.ContractCaller) Option {
+	return func(a *Authenticator) {
+		a.contractCaller = caller
+	}
+}
+
+// WithWalletVerifier sets the verifier of the wallet named `name`, a SeeAuth is verified by the verifier of its `WalletName`.
+// MetaMask (EVM wallets), JoyID, Solana, Bitcoin and Cosmos are built in.
+// The address derivation of the built-in JoyID verifier is unverified against real JoyID wallets, see `signature.PasskeyAddress`.
+// The built-in Cosmos verifier accepts addresses of any chain, use a `signature.CosmosVerifier` with `Prefix` to accept only one, wallets without a verifier are verified as EVM wallets
+func WithWalletVerifier(name WalletName, verifier signature.WalletVerifier) Option {
+	return func(a *Authenticator) {
+		a.wallets[name] = verifier
+	}
+}
+
+// WithNamespaceVerifier sets the verifier of the CAIP-2 `namespace`, a SeeAuth of which the wallet is a CAIP-10 account ID
+// (e.g. `eip155:1:0x…`) is verified by the verifier of the namespace whatever its `WalletName` is.
+// `eip155`, `solana`, `bip122` and `cosmos` are built in, they are the verifiers of MetaMask, Solana, Bitcoin and Cosmos
+func WithNamespaceVerifier(namespace string, verifier signature.WalletVerifier) Option {
+	return func(a *Authenticator) {
+		a.namespaces.Register(namespace, verifier)
+	}
+}
+
+// WithMessagePolicy sets what the SIWE message must satisfy, e.g. the accepted chain IDs, the statement and the max age.
+// The default is `signature.DefaultMessagePolicy`
+func WithMessagePolicy(policy signature.MessagePolicy) Option {
+	return func(a *Authenticator) {
+		a.messagePolicy = policy
+	}
+}
+
+// NewAuthenticator creates an Authenticator, options not given fall back to the values in `constants.go`
+func NewAuthenticator(opts ...Option) *Authenticator {
+	a := &Authenticator{
+		attesters:      proof.AttesterSet{{Address: attester, Label: "seedao-os"}},
+		proofLifetime:  proofLifetime,
+		allowedDomains: allowedDomains,
+		now:            time.Now,
+		blockNumber:    &common.RPCBlockNumberSource{URL: common.DefaultRPCURL},
+		chainID:        1,
+		wallets:        make(map[WalletName]signature.WalletVerifier),
+		namespaces:     signature.NewNamespaceRegistry(),
+		messagePolicy:  signature.DefaultMessagePolicy,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if len(a.deployments) == 0 {
+		a.deployments = proof.DefaultDeployments()
+	}
+	// the verifiers check the message at the time of the Authenticator
+	if a.messagePolicy.Now == nil {
+		a.messagePolicy.Now = a.now
+	}
+	if _, ok := a.wallets[WalletNameMetamask]; !ok {
+		a.wallets[WalletNameMetamask] = &signature.Verifier{Caller: a.contractCaller, Policy: &a.messagePolicy}
+	}
+	if _, ok := a.wallets[WalletNameJoyid]; !ok {
+		a.wallets[WalletNameJoyid] = &signature.JoyIDVerifier{RPID: signature.JoyIDRPID, Origins: signature.JoyIDOrigins, Policy: &a.messagePolicy}
+	}
+	if _, ok := a.wallets[WalletNameSolana]; !ok {
+		a.wallets[WalletNameSolana] = &signature.SolanaVerifier{Policy: &a.messagePolicy}
+	}
+	if _, ok := a.wallets[WalletNameBitcoin]; !ok {
+		a.wallets[WalletNameBitcoin] = &signature.BitcoinVerifier{Policy: &a.messagePolicy}
+	}
+	if _, ok := a.wallets[WalletNameCosmos]; !ok {
+		a.wallets[WalletNameCosmos] = &signature.CosmosVerifier{Policy: &a.messagePolicy}
+	}
+	if _, ok := a.namespaces.Lookup("eip155"); !ok {
+		a.namespaces.Register("eip155", a.wallets[WalletNameMetamask])
+	}
+	if _, ok := a.namespaces.Lookup("solana"); !ok {
+		a.namespaces.Register("solana", a.wallets[WalletNameSolana])
+	}
+	if _, ok := a.namespaces.Lookup("bip122"); !ok {
+		a.namespaces.Register("bip122", a.wallets[WalletNameBitcoin])
+	}
+	if _, ok := a.namespaces.Lookup("cosmos"); !ok {
+		a.namespaces.Register("cosmos", a.wallets[WalletNameCosmos])
+	}
+	if a.replayStore == nil {
+		a.replayStore = NewMemoryReplayStore(a.proofLifetime * 6)
+	}
+
+	return a
+}
+
+var defaultAuthenticator = NewAuthenticator()
+
+// walletVerifier returns the verifier of the wallet named `name`, it falls back to the verifier of MetaMask
+func (a *Authenticator) walletVerifier(name WalletName) signature.WalletVerifier {
+	if verifier, ok := a.wallets[name]; ok {
+		return verifier
+	}
+	return a.wallets[WalletNameMetamask]
+}
+
+// verifySignature verifies the signature of `wallet`, a CAIP-10 account ID is verified by the verifier of its namespace,
+// other wallets by the verifier of `walletName`
+func (a *Authenticator) verifySignature(ctx context.Context, walletName WalletName, wallet, domain, nonce, message, sig string) (*signature.Result, error) {
+	if signature.IsAccountID(wallet) {
+		return a.namespaces.Verify(ctx, wallet, domain, nonce, message, sig)
+	}
+	return a.walletVerifier(walletName).Verify(ctx, wallet, domain, nonce, message, sig)
+}
+
+// latestBlockNumber gets the latest block number.
+// A stale height (e.g. of a `common.BlockTracker` which can't reach the RPC) is still used, it's the best height known.
+// When the source fails, 0 is returned so that a broken RPC doesn't block signing in, but the error of `ctx` is always returned
+func (a *Authenticator) latestBlockNumber(ctx context.Context) (int64, error) {
+	number, err := a.blockNumber.BlockNumber(ctx)
+	if errors.Is(err, common.ErrBlockNumberStale) {
+		return number, nil
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, fmt.Errorf("get latest block number: %w", ctx.Err())
+		}
+		return 0, nil
+	}
+	return number, nil
+}
+
+// checkBlockNumber checks the block number in `nonce` is not older than 5 blocks
+func (a *Authenticator) checkBlockNumber(ctx context.Context, nonce string) error {
+	n, err := ParseNonce(nonce)
+	if err != nil {
+		return err
+	}
+	if n.ChainID != 0 && n.ChainID != a.chainID {
+		return autherr.New(autherr.CodeMalformed, fmt.Sprintf("nonce of chain %d, expect chain %d", n.ChainID, a.chainID))
+	}
+
+	number := n.BlockNumber
+	numberOnChain, err := a.latestBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if number != 0 && numberOnChain != 0 && numberOnChain-number > 5 {
+		return autherr.New(autherr.CodeStaleBlock, "block number too old")
+	}
+	return nil
+}
+
+// checkMessagePolicy checks the SIWE message by the message policy, whichever wallet signed it
+func (a *Authenticator) checkMessagePolicy(message string) error {
+	err := a.messagePolicy.Check(message, a.now())
+	if policyErr := messagePolicyError(err); policyErr != nil {
+		return policyErr
+	}
+	if err != nil {
+		return autherr.Wrap(autherr.CodeMalformed, "invalid message", err)
+	}
+	return nil
+}
+
+// messagePolicyError returns the error of the field which violates the message policy, nil if `err` isn't a `*signature.PolicyError`
+func messagePolicyError(err error) error {
+	var policyErr *signature.PolicyError
+	if errors.As(err, &policyErr) {
+		return autherr.Wrap(autherr.CodeMessagePolicy, "invalid message field "+policyErr.Field, err)
+	}
+	return nil
+}
+
+// signatureError returns the error of a failed `verifySignature`, a violation of the message policy is reported with its field
+func signatureError(err error) error {
+	if policyErr := messagePolicyError(err); policyErr != nil {
+		return policyErr
+	}
+	return autherr.Wrap(autherr.CodeSignatureMismatch, "Invalid signature", err)
+}
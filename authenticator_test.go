@@ -0,0 +1,478 @@
+package seeauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Taoist-Labs/see-auth-go/common"
+	"github.com/Taoist-Labs/see-auth-go/proof"
+	"github.com/Taoist-Labs/see-auth-go/signature"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	walletKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+	wallet    = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	recipient = "0x0000000000000000000000000000000000000000"
+
+	stagingKey      = "5de4111afa1a4b94908f83103eb1f1706367c2e68ca870fc3fb9a804cdab365a"
+	stagingAttester = "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"
+)
+
+// issueSeeAuth signs in `wallet` and issues a proof by the staging attester
+func issueSeeAuth(t *testing.T, a *Authenticator) *SeeAuth {
+	nonce := a.GenerateNonce()
+	message, sig, err := signature.Sign(nonce, 60*time.Second, walletKey)
+	if err != nil {
+		t.Fatalf("signature.Sign() error = %v", err)
+	}
+	seeAuth, err := a.Auth(&SignatureParams{
+		WalletName: WalletNameMetamask,
+		Wallet:     wallet,
+		Domain:     "app.seedao.xyz",
+		Nonce:      nonce,
+		Message:    message,
+		Signature:  sig,
+	}, &ProofParams{
+		Recipient:  recipient,
+		Schema:     &proof.SchemaData{Signature: sig, Wallet: wallet, Vendor: "os+"},
+		PrivateKey: stagingKey,
+	})
+	if err != nil {
+		t.Fatalf("Auth() error = %v", err)
+	}
+	return seeAuth
+}
+
+func TestAuthenticator(t *testing.T) {
+	staging := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	production := NewAuthenticator(WithBlockNumberSource(common.StaticBlockNumber(0)))
+
+	tests := []struct {
+		name          string
+		authenticator *Authenticator
+		wantErr       error
+	}{
+		{
+			name:          "ok",
+			authenticator: staging,
+			wantErr:       nil,
+		},
+		{
+			name:          "attester not trusted",
+			authenticator: production,
+			wantErr:       ErrAttesterMismatch,
+		},
+		{
+			name: "proof expired",
+			authenticator: NewAuthenticator(
+				WithAttester(stagingAttester),
+				WithBlockNumberSource(common.StaticBlockNumber(0)),
+				WithClock(func() time.Time { return time.Now().Add(2 * time.Minute) }),
+			),
+			wantErr: ErrProofExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.authenticator.SeeDAOAuth(recipient, issueSeeAuth(t, staging))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SeeDAOAuth() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got != wallet {
+				t.Errorf("SeeDAOAuth() got = %v, want = %v", got, wallet)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Reuse(t *testing.T) {
+	a := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	seeAuth := issueSeeAuth(t, a)
+
+	if _, err := a.SeeDAOAuth(recipient, seeAuth); err != nil {
+		t.Fatalf("SeeDAOAuth() first error = %v", err)
+	}
+	if _, err := a.SeeDAOAuth(recipient, seeAuth); !errors.Is(err, ErrReplay) {
+		t.Fatalf("SeeDAOAuth() reuse error = %v, want = %v", err, ErrReplay)
+	}
+}
+
+func TestAuthenticator_Context(t *testing.T) {
+	// the source blocks until `ctx` is done, like a stalled RPC
+	stalled := common.BlockNumberFunc(func(ctx context.Context) (int64, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	issuer := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	seeAuth := issueSeeAuth(t, issuer)
+	a := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(stalled))
+
+	newCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	if _, err := a.GenerateNonceContext(newCtx()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GenerateNonceContext() error = %v, want = %v", err, context.DeadlineExceeded)
+	}
+	signatureParams := &SignatureParams{
+		WalletName: seeAuth.WalletName,
+		Wallet:     seeAuth.Wallet,
+		Domain:     seeAuth.Signature.Domain,
+		Nonce:      seeAuth.Signature.Nonce,
+		Message:    seeAuth.Signature.Message,
+		Signature:  seeAuth.Signature.Signature,
+	}
+	if _, err := a.AuthContext(newCtx(), signatureParams, &ProofParams{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("AuthContext() error = %v, want = %v", err, context.DeadlineExceeded)
+	}
+	if _, err := a.SeeDAOAuthContext(newCtx(), recipient, seeAuth); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SeeDAOAuthContext() error = %v, want = %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	issuer := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	seeAuth := issueSeeAuth(t, issuer)
+
+	// the staging key is rotated to the default attester, both are trusted during the overlap
+	a := NewAuthenticator(
+		WithAttesters(
+			proof.Attester{Address: stagingAttester, NotAfter: time.Now().Add(time.Hour), Label: "staging-2024"},
+			proof.Attester{Address: attester, NotBefore: time.Now().Add(-time.Hour), Label: "staging-2025"},
+		),
+		WithBlockNumberSource(common.StaticBlockNumber(0)),
+	)
+	got, err := a.Authenticate(context.Background(), recipient, seeAuth)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if got.Wallet != wallet || got.Attester.Label != "staging-2024" || got.SignerType != signature.SignerEOA {
+		t.Errorf("Authenticate() got = %+v", got)
+	}
+}
+
+// rejectVerifier rejects every signature
+type rejectVerifier struct{}
+
+func (rejectVerifier) Verify(ctx context.Context, wallet, domain, nonce, message, sig string) (*signature.Result, error) {
+	return nil, errors.New("rejected")
+}
+
+func TestAuthenticator_WalletVerifier(t *testing.T) {
+	a := NewAuthenticator(
+		WithAttester(stagingAttester),
+		WithBlockNumberSource(common.StaticBlockNumber(0)),
+		WithWalletVerifier(WalletNameJoyid, rejectVerifier{}),
+	)
+
+	tests := []struct {
+		name       string
+		walletName WalletName
+		wantErr    error
+	}{
+		{name: "metamask", walletName: WalletNameMetamask, wantErr: nil},
+		{name: "unknown wallet falls back to metamask", walletName: "rainbow", wantErr: nil},
+		{name: "joyid", walletName: WalletNameJoyid, wantErr: ErrSignatureMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seeAuth := issueSeeAuth(t, a)
+			seeAuth.WalletName = tt.walletName
+
+			_, err := a.SeeDAOAuth(recipient, seeAuth)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SeeDAOAuth() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_MessagePolicy(t *testing.T) {
+	staging := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+
+	tests := []struct {
+		name    string
+		policy  signature.MessagePolicy
+		wantErr error
+	}{
+		{name: "default", policy: signature.DefaultMessagePolicy, wantErr: nil},
+		{name: "chain accepted", policy: signature.MessagePolicy{ChainIDs: []int{1}, Statement: "Welcome to SeeDAO!"}, wantErr: nil},
+		{name: "chain not accepted", policy: signature.MessagePolicy{ChainIDs: []int{10}}, wantErr: ErrMessagePolicy},
+		{name: "statement not match", policy: signature.MessagePolicy{Statement: "Welcome!"}, wantErr: ErrMessagePolicy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAuthenticator(
+				WithAttester(stagingAttester),
+				WithBlockNumberSource(common.StaticBlockNumber(0)),
+				WithMessagePolicy(tt.policy),
+			)
+			_, err := a.SeeDAOAuth(recipient, issueSeeAuth(t, staging))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("SeeDAOAuth() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Clock(t *testing.T) {
+	// everything is checked at the time of the clock, the message was fresh then but is too old now
+	past := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := NewAuthenticator(
+		WithAttester(stagingAttester),
+		WithBlockNumberSource(common.StaticBlockNumber(0)),
+		WithClock(func() time.Time { return past }),
+	)
+	nonce := a.GenerateNonce()
+	message, err := signature.NewChallenge(signature.ChallengeOptions{
+		Address:        wallet,
+		Domain:         "app.seedao.xyz",
+		URI:            "https://app.seedao.xyz",
+		ChainID:        1,
+		Nonce:          nonce,
+		Statement:      "Welcome to SeeDAO!",
+		IssuedAt:       past,
+		ExpirationTime: past.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("NewChallenge() error = %v", err)
+	}
+	key, err := crypto.HexToECDSA(walletKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+
+	seeAuth, err := a.Auth(&SignatureParams{
+		WalletName: WalletNameMetamask,
+		Wallet:     wallet,
+		Domain:     "app.seedao.xyz",
+		Nonce:      nonce,
+		Message:    message,
+		Signature:  hexutil.Encode(sig),
+	}, &ProofParams{
+		Recipient:  recipient,
+		Schema:     &proof.SchemaData{Signature: hexutil.Encode(sig), Wallet: wallet, Vendor: "os+"},
+		PrivateKey: stagingKey,
+	})
+	if err != nil {
+		t.Fatalf("Auth() error = %v", err)
+	}
+	if _, err = a.SeeDAOAuth(recipient, seeAuth); err != nil {
+		t.Errorf("SeeDAOAuth() error = %v", err)
+	}
+
+	// the same message is checked at the current time by an Authenticator without clock
+	b := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	if _, err = b.SeeDAOAuth(recipient, seeAuth); err == nil {
+		t.Errorf("SeeDAOAuth() without clock, want error")
+	}
+}
+
+// policyVerifier rejects every message as a violation of the message policy
+type policyVerifier struct{}
+
+func (policyVerifier) Verify(ctx context.Context, wallet, domain, nonce, message, sig string) (*signature.Result, error) {
+	return nil, &signature.PolicyError{Field: signature.FieldChainID, Msg: "chain not accepted"}
+}
+
+func TestAuthenticator_PolicyError(t *testing.T) {
+	a := NewAuthenticator(
+		WithAttester(stagingAttester),
+		WithBlockNumberSource(common.StaticBlockNumber(0)),
+		WithWalletVerifier(WalletNameJoyid, policyVerifier{}),
+	)
+	seeAuth := issueSeeAuth(t, a)
+	seeAuth.WalletName = WalletNameJoyid
+
+	_, err := a.SeeDAOAuth(recipient, seeAuth)
+	if !errors.Is(err, ErrMessagePolicy) {
+		t.Errorf("SeeDAOAuth() error = %v, want = %v", err, ErrMessagePolicy)
+	}
+}
+
+func TestAuthenticator_Solana(t *testing.T) {
+	a := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	_, key, _ := ed25519.GenerateKey(nil)
+	solanaWallet := signature.SolanaAddress(key.Public().(ed25519.PublicKey))
+
+	nonce := a.GenerateNonce()
+	message := (&signature.Message{
+		Domain:     "app.seedao.xyz",
+		Blockchain: "Solana",
+		Address:    solanaWallet,
+		Statement:  "Welcome to SeeDAO!",
+		URI:        "https://app.seedao.xyz",
+		Version:    "1",
+		ChainID:    "mainnet",
+		Nonce:      nonce,
+		IssuedAt:   time.Now().UTC().Format(time.RFC3339),
+	}).String()
+	sig := "0x" + hex.EncodeToString(ed25519.Sign(key, []byte(message)))
+
+	seeAuth, err := a.Auth(&SignatureParams{
+		WalletName: WalletNameSolana,
+		Wallet:     solanaWallet,
+		Domain:     "app.seedao.xyz",
+		Nonce:      nonce,
+		Message:    message,
+		Signature:  sig,
+	}, &ProofParams{
+		Recipient:  recipient,
+		Schema:     &proof.SchemaData{Signature: sig, Wallet: solanaWallet, Vendor: "os+"},
+		PrivateKey: stagingKey,
+	})
+	if err != nil {
+		t.Fatalf("Auth() error = %v", err)
+	}
+
+	got, err := a.SeeDAOAuth(recipient, seeAuth)
+	if err != nil {
+		t.Fatalf("SeeDAOAuth() error = %v", err)
+	}
+	if got != solanaWallet {
+		t.Errorf("SeeDAOAuth() got = %v, want = %v", got, solanaWallet)
+	}
+
+	// a Solana signature is not accepted as an EVM one
+	seeAuth.WalletName = WalletNameMetamask
+	if _, err = a.SeeDAOAuth(recipient, seeAuth); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("SeeDAOAuth() error = %v, wantErr = %v", err, ErrSignatureMismatch)
+	}
+}
+
+func TestAuthenticator_AccountID(t *testing.T) {
+	a := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	accountID := "eip155:1:" + wallet
+
+	nonce := a.GenerateNonce()
+	message, sig, err := signature.Sign(nonce, 60*time.Second, walletKey)
+	if err != nil {
+		t.Fatalf("signature.Sign() error = %v", err)
+	}
+	seeAuth, err := a.Auth(&SignatureParams{
+		Wallet:    accountID,
+		Domain:    "app.seedao.xyz",
+		Nonce:     nonce,
+		Message:   message,
+		Signature: sig,
+	}, &ProofParams{
+		Recipient:  recipient,
+		Schema:     &proof.SchemaData{Signature: sig, Wallet: accountID, Vendor: "os+"},
+		PrivateKey: stagingKey,
+	})
+	if err != nil {
+		t.Fatalf("Auth() error = %v", err)
+	}
+
+	got, err := a.SeeDAOAuth(recipient, seeAuth)
+	if err != nil {
+		t.Fatalf("SeeDAOAuth() error = %v", err)
+	}
+	if got != accountID {
+		t.Errorf("SeeDAOAuth() got = %v, want = %v", got, accountID)
+	}
+
+	// the namespace decides the verifier, not the wallet name
+	seeAuth.WalletName = WalletNameSolana
+	if _, err = NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0))).SeeDAOAuth(recipient, seeAuth); err != nil {
+		t.Errorf("SeeDAOAuth() error = %v", err)
+	}
+
+	b := NewAuthenticator(
+		WithAttester(stagingAttester),
+		WithBlockNumberSource(common.StaticBlockNumber(0)),
+		WithNamespaceVerifier("eip155", rejectVerifier{}),
+	)
+	if _, err = b.SeeDAOAuth(recipient, seeAuth); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("SeeDAOAuth() error = %v, wantErr = %v", err, ErrSignatureMismatch)
+	}
+}
+
+func TestAuthenticator_Cosmos(t *testing.T) {
+	a := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	key, _ := btcec.NewPrivateKey()
+	cosmosWallet := signature.CosmosAddress("cosmos", key.PubKey())
+
+	nonce := a.GenerateNonce()
+	message := (&signature.Message{
+		Domain:     "app.seedao.xyz",
+		Blockchain: "Cosmos",
+		Address:    cosmosWallet,
+		Statement:  "Welcome to SeeDAO!",
+		URI:        "https://app.seedao.xyz",
+		Version:    "1",
+		ChainID:    "cosmoshub-4",
+		Nonce:      nonce,
+		IssuedAt:   time.Now().UTC().Format(time.RFC3339),
+	}).String()
+	doc, _ := signature.NewADR036SignDoc(cosmosWallet, []byte(message)).Bytes()
+	hash := sha256.Sum256(doc)
+	compact, _ := ecdsa.SignCompact(key, hash[:], true)
+	var stdSignature signature.StdSignature
+	stdSignature.PubKey.Type = "tendermint/PubKeySecp256k1"
+	stdSignature.PubKey.Value = key.PubKey().SerializeCompressed()
+	stdSignature.Signature = compact[1:]
+	b, _ := json.Marshal(&stdSignature)
+	sig := string(b)
+
+	seeAuth, err := a.Auth(&SignatureParams{
+		WalletName: WalletNameCosmos,
+		Wallet:     cosmosWallet,
+		Domain:     "app.seedao.xyz",
+		Nonce:      nonce,
+		Message:    message,
+		Signature:  sig,
+	}, &ProofParams{
+		Recipient:  recipient,
+		Schema:     &proof.SchemaData{Signature: sig, Wallet: cosmosWallet, Vendor: "os+"},
+		PrivateKey: stagingKey,
+	})
+	if err != nil {
+		t.Fatalf("Auth() error = %v", err)
+	}
+
+	// only Osmosis addresses are accepted
+	osmosis := NewAuthenticator(
+		WithAttester(stagingAttester),
+		WithBlockNumberSource(common.StaticBlockNumber(0)),
+		WithWalletVerifier(WalletNameCosmos, &signature.CosmosVerifier{Prefix: "osmo"}),
+	)
+	if _, err = osmosis.SeeDAOAuth(recipient, seeAuth); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("SeeDAOAuth() error = %v, wantErr = %v", err, ErrSignatureMismatch)
+	}
+
+	got, err := a.SeeDAOAuth(recipient, seeAuth)
+	if err != nil {
+		t.Fatalf("SeeDAOAuth() error = %v", err)
+	}
+	if got != cosmosWallet {
+		t.Errorf("SeeDAOAuth() got = %v, want = %v", got, cosmosWallet)
+	}
+}
+
+func TestAuthenticator_JoyID(t *testing.T) {
+	// the built-in JoyID verifier checks the origin and `rpIdHash` of the assertion
+	v, ok := NewAuthenticator().walletVerifier(WalletNameJoyid).(*signature.JoyIDVerifier)
+	if !ok || v.RPID != signature.JoyIDRPID || len(v.Origins) == 0 {
+		t.Errorf("walletVerifier() of JoyID = %+v", v)
+	}
+}
@@ -0,0 +1,76 @@
+package autherr
+
+import "errors"
+
+// Code classifies an AuthError, it is stable and can be mapped to HTTP status codes or client messages
+type Code string
+
+const (
+	CodeMalformed         Code = "malformed"          // the input can't be parsed
+	CodeReplay            Code = "replay"             // the proof has been used
+	CodeStaleBlock        Code = "stale_block"        // the block number in the nonce is too old
+	CodeProofExpired      Code = "proof_expired"      // the proof is expired
+	CodeRecipientMismatch Code = "recipient_mismatch" // the proof is issued to another recipient
+	CodeAttesterMismatch  Code = "attester_mismatch"  // the proof is not signed by a trusted attester
+	CodeSignatureMismatch Code = "signature_mismatch" // the signature is invalid or not signed by the wallet
+	CodePayloadMismatch   Code = "payload_mismatch"   // the data in the proof doesn't match the request
+	CodeDomainNotAllowed  Code = "domain_not_allowed" // the signature is requested by a domain not allowed
+	CodeMessagePolicy     Code = "message_policy"     // a field of the SIWE message violates the message policy
+)
+
+// AuthError is returned by all verification paths.
+// Two AuthErrors are equal for `errors.Is` when their codes are equal, so the sentinel errors can be used as targets.
+// `Err` is the cause, it can be unwrapped.
+type AuthError struct {
+	Code Code
+	Msg  string
+	Err  error
+}
+
+func (e *AuthError) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+func (e *AuthError) Is(target error) bool {
+	t, ok := target.(*AuthError)
+	return ok && t.Code == e.Code
+}
+
+// New creates an AuthError
+func New(code Code, msg string) error {
+	return &AuthError{Code: code, Msg: msg}
+}
+
+// Wrap creates an AuthError caused by `err`
+func Wrap(code Code, msg string, err error) error {
+	return &AuthError{Code: code, Msg: msg, Err: err}
+}
+
+// CodeOf returns the code of the first AuthError in the chain of `err`, or "" if there is none
+func CodeOf(err error) Code {
+	var e *AuthError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+var (
+	ErrMalformed         = &AuthError{Code: CodeMalformed, Msg: "malformed input"}
+	ErrReplay            = &AuthError{Code: CodeReplay, Msg: "proof reused"}
+	ErrStaleBlock        = &AuthError{Code: CodeStaleBlock, Msg: "block number too old"}
+	ErrProofExpired      = &AuthError{Code: CodeProofExpired, Msg: "proof expired"}
+	ErrRecipientMismatch = &AuthError{Code: CodeRecipientMismatch, Msg: "recipient not match"}
+	ErrAttesterMismatch  = &AuthError{Code: CodeAttesterMismatch, Msg: "attester not match"}
+	ErrSignatureMismatch = &AuthError{Code: CodeSignatureMismatch, Msg: "signature not match"}
+	ErrPayloadMismatch   = &AuthError{Code: CodePayloadMismatch, Msg: "payload not match"}
+	ErrDomainNotAllowed  = &AuthError{Code: CodeDomainNotAllowed, Msg: "domain not allowed"}
+	ErrMessagePolicy     = &AuthError{Code: CodeMessagePolicy, Msg: "message policy violated"}
+)
@@ -0,0 +1,54 @@
+package autherr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestAuthError(t *testing.T) {
+	cause := strconv.ErrSyntax
+	err := fmt.Errorf("verify: %w", Wrap(CodeMalformed, "Proof Error: invalid expiration time", cause))
+
+	if !errors.Is(err, ErrMalformed) {
+		t.Errorf("errors.Is(err, ErrMalformed) = false, want = true")
+	}
+	if errors.Is(err, ErrProofExpired) {
+		t.Errorf("errors.Is(err, ErrProofExpired) = true, want = false")
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("errors.Is(err, cause) = false, want = true")
+	}
+
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("errors.As(err, *AuthError) = false, want = true")
+	}
+	if authErr.Code != CodeMalformed {
+		t.Errorf("AuthError.Code = %v, want = %v", authErr.Code, CodeMalformed)
+	}
+	if got, want := authErr.Error(), "Proof Error: invalid expiration time: invalid syntax"; got != want {
+		t.Errorf("AuthError.Error() = %v, want = %v", got, want)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{name: "auth error", err: New(CodeReplay, "Reuse proof"), want: CodeReplay},
+		{name: "wrapped", err: fmt.Errorf("login: %w", ErrStaleBlock), want: CodeStaleBlock},
+		{name: "other error", err: errors.New("other"), want: ""},
+		{name: "nil", err: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}
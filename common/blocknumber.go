@@ -0,0 +1,74 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BlockNumberSource provides the latest block number
+type BlockNumberSource interface {
+	BlockNumber(ctx context.Context) (int64, error)
+}
+
+// DefaultRPCURL is the JSON-RPC endpoint used by `GetLatestBlockNumber`
+const DefaultRPCURL = "https://rpc.ankr.com/eth"
+
+// DefaultRPCTimeout is used when `RPCBlockNumberSource.Timeout` is zero
+const DefaultRPCTimeout = 5 * time.Second
+
+// RPCBlockNumberSource gets the latest block number from a JSON-RPC endpoint by `eth_blockNumber`
+type RPCBlockNumberSource struct {
+	URL     string
+	Headers http.Header   // e.g. API keys of the RPC provider
+	Timeout time.Duration // timeout of every call, `DefaultRPCTimeout` if zero
+}
+
+func (s *RPCBlockNumberSource) BlockNumber(ctx context.Context) (int64, error) {
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = DefaultRPCTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := rpc.DialOptions(ctx, s.URL, rpc.WithHeaders(s.Headers))
+	if err != nil {
+		return 0, contextError(ctx, err)
+	}
+	defer client.Close()
+
+	var number hexutil.Uint64
+	err = client.CallContext(ctx, &number, "eth_blockNumber")
+	if err != nil {
+		return 0, contextError(ctx, err)
+	}
+	return int64(number), nil
+}
+
+// contextError returns the error of `ctx` wrapped when `ctx` is done, otherwise `err`,
+// so that callers can check cancellation by `errors.Is(err, context.Canceled)` whatever the RPC client returns
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("eth_blockNumber: %w", ctx.Err())
+	}
+	return err
+}
+
+// StaticBlockNumber always provides the same block number, it is useful for testing
+type StaticBlockNumber int64
+
+func (n StaticBlockNumber) BlockNumber(context.Context) (int64, error) {
+	return int64(n), nil
+}
+
+// BlockNumberFunc adapts a function to a BlockNumberSource
+type BlockNumberFunc func(ctx context.Context) (int64, error)
+
+func (f BlockNumberFunc) BlockNumber(ctx context.Context) (int64, error) {
+	return f(ctx)
+}
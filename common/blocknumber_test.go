@@ -0,0 +1,102 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newRPCServer starts a JSON-RPC server which answers `eth_blockNumber` with `number` after `delay`
+func newRPCServer(t *testing.T, number string, delay time.Duration) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != "eth_blockNumber" {
+			t.Errorf("method = %v, want = eth_blockNumber", req.Method)
+		}
+		if r.Header.Get("X-Api-Key") != "secret" {
+			t.Errorf("header X-Api-Key = %v, want = secret", r.Header.Get("X-Api-Key"))
+		}
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": number})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRPCBlockNumberSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		delay   time.Duration
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:    "ok",
+			want:    0x12a05f2,
+			wantErr: false,
+		},
+		{
+			name:    "timeout",
+			delay:   200 * time.Millisecond,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newRPCServer(t, "0x12a05f2", tt.delay)
+			source := &RPCBlockNumberSource{
+				URL:     server.URL,
+				Headers: http.Header{"X-Api-Key": []string{"secret"}},
+				Timeout: 50 * time.Millisecond,
+			}
+
+			got, err := source.BlockNumber(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BlockNumber() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("BlockNumber() got = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaticBlockNumber(t *testing.T) {
+	got, err := StaticBlockNumber(100).BlockNumber(context.Background())
+	if err != nil || got != 100 {
+		t.Errorf("BlockNumber() got = %v, %v, want = 100", got, err)
+	}
+}
+
+func TestBlockNumberFunc(t *testing.T) {
+	wantErr := errors.New("no block")
+	_, err := BlockNumberFunc(func(context.Context) (int64, error) { return 0, wantErr }).BlockNumber(context.Background())
+	if !errors.Is(err, wantErr) {
+		t.Errorf("BlockNumber() error = %v, want = %v", err, wantErr)
+	}
+}
+
+func TestRPCBlockNumberSource_Canceled(t *testing.T) {
+	server := newRPCServer(t, "0x1", 200*time.Millisecond)
+	source := &RPCBlockNumberSource{
+		URL:     server.URL,
+		Headers: http.Header{"X-Api-Key": []string{"secret"}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err := source.BlockNumber(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("BlockNumber() error = %v, want = %v", err, context.Canceled)
+	}
+}
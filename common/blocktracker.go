@@ -0,0 +1,198 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	ErrBlockNumberUnavailable = errors.New("block number unavailable")
+	ErrBlockNumberStale       = errors.New("block number stale")
+)
+
+// BlockTrackerConfig configures a BlockTracker
+type BlockTrackerConfig struct {
+	URL      string        // `http(s)://` endpoints are polled, `ws(s)://` endpoints are subscribed
+	Headers  http.Header   // e.g. API keys of the RPC provider
+	Interval time.Duration // polling interval, 12 seconds (one block) if zero
+	Timeout  time.Duration // timeout of every call, `DefaultRPCTimeout` if zero
+	MaxAge   time.Duration // the height is stale when it is not updated for `MaxAge`, 5 intervals if zero
+}
+
+// BlockTracker keeps the latest block number in memory, so that `GenerateNonce` and the freshness check
+// don't call the RPC on the login hot path. It polls `eth_blockNumber` every interval,
+// or subscribes `newHeads` when a websocket endpoint is given and falls back to polling when the subscription fails.
+// It is a BlockNumberSource, it must be closed by `Close` when no longer used.
+type BlockTracker struct {
+	cfg    BlockTrackerConfig
+	client *rpc.Client
+
+	mu        sync.RWMutex
+	number    int64
+	updatedAt time.Time
+	err       error
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewBlockTracker connects to `cfg.URL`, gets the first height and starts tracking in background.
+// The first height is best-effort, a failed RPC doesn't fail it, see `Err`.
+func NewBlockTracker(ctx context.Context, cfg BlockTrackerConfig) (*BlockTracker, error) {
+	if cfg.Interval == 0 {
+		cfg.Interval = 12 * time.Second
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultRPCTimeout
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = 5 * cfg.Interval
+	}
+
+	client, err := rpc.DialOptions(ctx, cfg.URL, rpc.WithHeaders(cfg.Headers))
+	if err != nil {
+		return nil, err
+	}
+
+	loopCtx, cancel := context.WithCancel(context.Background())
+	t := &BlockTracker{
+		cfg:    cfg,
+		client: client,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	t.poll(ctx)
+	go t.run(loopCtx)
+
+	return t, nil
+}
+
+// BlockNumber returns the tracked height without calling the RPC.
+// It returns ErrBlockNumberUnavailable before the first height is got, and ErrBlockNumberStale when the height is stale.
+func (t *BlockTracker) BlockNumber(context.Context) (int64, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.updatedAt.IsZero() {
+		return 0, ErrBlockNumberUnavailable
+	}
+	if time.Since(t.updatedAt) > t.cfg.MaxAge {
+		return t.number, ErrBlockNumberStale
+	}
+	return t.number, nil
+}
+
+// Staleness returns how long the height has not been updated, it is 0 before the first height is got
+func (t *BlockTracker) Staleness() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.updatedAt.IsZero() {
+		return 0
+	}
+	return time.Since(t.updatedAt)
+}
+
+// Stale reports whether the height is missing or not updated for `MaxAge`
+func (t *BlockTracker) Stale() bool {
+	_, err := t.BlockNumber(context.Background())
+	return err != nil
+}
+
+// Err returns the error of the last update, or nil if it succeeded
+func (t *BlockTracker) Err() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.err
+}
+
+// Close stops tracking and closes the connection, it is safe to call more than once
+func (t *BlockTracker) Close() error {
+	t.closeOnce.Do(func() {
+		t.cancel()
+		<-t.done
+		t.client.Close()
+	})
+	return nil
+}
+
+func (t *BlockTracker) run(ctx context.Context) {
+	defer close(t.done)
+
+	if strings.HasPrefix(t.cfg.URL, "ws://") || strings.HasPrefix(t.cfg.URL, "wss://") {
+		t.subscribe(ctx)
+	}
+
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.poll(ctx)
+		}
+	}
+}
+
+// subscribe updates the height by `newHeads` until the subscription fails or `ctx` is done
+func (t *BlockTracker) subscribe(ctx context.Context) {
+	heads := make(chan struct {
+		Number hexutil.Uint64 `json:"number"`
+	})
+	sub, err := t.client.EthSubscribe(ctx, heads, "newHeads")
+	if err != nil {
+		t.update(0, err)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("subscription closed")
+			}
+			t.update(0, err)
+			return
+		case head := <-heads:
+			t.update(int64(head.Number), nil)
+		}
+	}
+}
+
+func (t *BlockTracker) poll(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, t.cfg.Timeout)
+	defer cancel()
+
+	var number hexutil.Uint64
+	err := t.client.CallContext(ctx, &number, "eth_blockNumber")
+	t.update(int64(number), err)
+}
+
+// update records the result of an update, a failed update keeps the last height.
+// A successful update keeps the height from going back, e.g. after a failover to a lagging node or a reorg, but it's not stale
+func (t *BlockTracker) update(number int64, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.err = err
+	if err != nil {
+		return
+	}
+	if number > t.number {
+		t.number = number
+	}
+	t.updatedAt = time.Now()
+}
@@ -0,0 +1,181 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ethService is a stub of the `eth` namespace, the height grows on every `eth_blockNumber`
+type ethService struct {
+	number int64
+	fail   atomic.Bool
+	lag    atomic.Bool // a lagging node, the height is always 1
+}
+
+func (s *ethService) BlockNumber() (hexutil.Uint64, error) {
+	if s.fail.Load() {
+		return 0, errors.New("rpc down")
+	}
+	if s.lag.Load() {
+		return 1, nil
+	}
+	return hexutil.Uint64(atomic.AddInt64(&s.number, 1)), nil
+}
+
+func (s *ethService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for number := 100; ; number++ {
+			select {
+			case <-sub.Err():
+				return
+			case <-time.After(5 * time.Millisecond):
+				_ = notifier.Notify(sub.ID, map[string]any{"number": hexutil.Uint64(number)})
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// newEthServer serves a stub `eth` namespace over HTTP, or over websocket when `websocket` is true
+func newEthServer(t *testing.T, websocket bool) (*ethService, *httptest.Server) {
+	service := &ethService{}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+
+	var httpServer *httptest.Server
+	if websocket {
+		httpServer = httptest.NewServer(server.WebsocketHandler(nil))
+	} else {
+		httpServer = httptest.NewServer(server)
+	}
+	t.Cleanup(httpServer.Close)
+	return service, httpServer
+}
+
+// waitFor waits until `cond` is true or fails the test after one second
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBlockTracker_Poll(t *testing.T) {
+	service, server := newEthServer(t, false)
+
+	tracker, err := NewBlockTracker(context.Background(), BlockTrackerConfig{
+		URL:      server.URL,
+		Interval: 10 * time.Millisecond,
+		MaxAge:   50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracker.Close()
+
+	// the first height is got by `NewBlockTracker`
+	if got, err := tracker.BlockNumber(context.Background()); err != nil || got != 1 {
+		t.Fatalf("BlockNumber() = %v, %v, want = 1", got, err)
+	}
+	waitFor(t, func() bool {
+		got, _ := tracker.BlockNumber(context.Background())
+		return got >= 3
+	})
+
+	// the height becomes stale when the RPC is down
+	service.fail.Store(true)
+	waitFor(t, tracker.Stale)
+	if _, err := tracker.BlockNumber(context.Background()); !errors.Is(err, ErrBlockNumberStale) {
+		t.Errorf("BlockNumber() error = %v, want = %v", err, ErrBlockNumberStale)
+	}
+	if tracker.Err() == nil {
+		t.Errorf("Err() = nil, want error")
+	}
+	if tracker.Staleness() < 50*time.Millisecond {
+		t.Errorf("Staleness() = %v, want >= 50ms", tracker.Staleness())
+	}
+
+	// it is safe to close more than once
+	_ = tracker.Close()
+	_ = tracker.Close()
+}
+
+func TestBlockTracker_Lagging(t *testing.T) {
+	service, server := newEthServer(t, false)
+
+	tracker, err := NewBlockTracker(context.Background(), BlockTrackerConfig{
+		URL:      server.URL,
+		Interval: 10 * time.Millisecond,
+		MaxAge:   50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracker.Close()
+
+	waitFor(t, func() bool {
+		got, _ := tracker.BlockNumber(context.Background())
+		return got >= 3
+	})
+
+	// a lagging node doesn't take the height back, and the tracker is not stale while the polls succeed
+	service.lag.Store(true)
+	want, _ := tracker.BlockNumber(context.Background())
+	time.Sleep(100 * time.Millisecond)
+	got, err := tracker.BlockNumber(context.Background())
+	if err != nil || got < want {
+		t.Errorf("BlockNumber() = %v, %v, want >= %v", got, err, want)
+	}
+}
+
+func TestBlockTracker_Subscribe(t *testing.T) {
+	_, server := newEthServer(t, true)
+
+	tracker, err := NewBlockTracker(context.Background(), BlockTrackerConfig{
+		URL:      "ws" + strings.TrimPrefix(server.URL, "http"),
+		Interval: time.Hour, // the height must come from `newHeads`
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracker.Close()
+
+	waitFor(t, func() bool {
+		got, _ := tracker.BlockNumber(context.Background())
+		return got >= 100
+	})
+}
+
+func TestBlockTracker_Unavailable(t *testing.T) {
+	service, server := newEthServer(t, false)
+	service.fail.Store(true)
+
+	tracker, err := NewBlockTracker(context.Background(), BlockTrackerConfig{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracker.Close()
+
+	if _, err := tracker.BlockNumber(context.Background()); !errors.Is(err, ErrBlockNumberUnavailable) {
+		t.Errorf("BlockNumber() error = %v, want = %v", err, ErrBlockNumberUnavailable)
+	}
+}
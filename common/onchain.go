@@ -1,28 +1,16 @@
 package common
 
-import (
-	"strconv"
+import "context"
 
-	"github.com/ethereum/go-ethereum/rpc"
-)
+var defaultBlockNumberSource = &RPCBlockNumberSource{URL: DefaultRPCURL}
 
-type block struct {
-	Number string
-}
-
-// GetLatestBlockNumber
+// GetLatestBlockNumber gets the latest block number from `DefaultRPCURL`
 // mint one block every 12 seconds
 func GetLatestBlockNumber() (blockNumber int64, err error) {
-	client, err := rpc.Dial("https://rpc.ankr.com/eth")
-	if err != nil {
-		return
-	}
-	defer client.Close()
-	var lastBlock block
-	err = client.Call(&lastBlock, "eth_getBlockByNumber", "latest", true)
-	if err != nil {
-		return
-	}
+	return defaultBlockNumberSource.BlockNumber(context.Background())
+}
 
-	return strconv.ParseInt(lastBlock.Number, 0, 0)
+// GetLatestBlockNumberContext is like `GetLatestBlockNumber`, but it stops waiting for the RPC when `ctx` is done
+func GetLatestBlockNumberContext(ctx context.Context) (blockNumber int64, err error) {
+	return defaultBlockNumberSource.BlockNumber(ctx)
 }
@@ -1,9 +1,17 @@
 package common
 
-import "testing"
+import (
+	"errors"
+	"net"
+	"testing"
+)
 
 func TestGetLatestBlockNumber(t *testing.T) {
 	got, err := GetLatestBlockNumber()
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		t.Skipf("%s is unreachable: %v", DefaultRPCURL, err)
+	}
 	if err != nil {
 		t.Fatal(err)
 	}
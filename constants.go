@@ -6,4 +6,5 @@ const attester = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
 
 var proofLifetime = 60 * time.Second
 
-var allowedDomains = []string{""}
+// see `domainAllowed` for the format
+var allowedDomains = []string{"seedao.xyz", "*.seedao.xyz"}
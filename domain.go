@@ -0,0 +1,60 @@
+package seeauth
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/Taoist-Labs/see-auth-go/autherr"
+	"github.com/Taoist-Labs/see-auth-go/signature"
+)
+
+// domainAllowed reports whether `domain` (`host` or `host:port`) matches one of `patterns`.
+// A pattern is an exact host (`app.seedao.xyz`), a wildcard of subdomains (`*.seedao.xyz`, which doesn't match `seedao.xyz`)
+// or `*` for any host, optionally followed by a port (`localhost:3000`).
+// A pattern without port only matches a domain without port.
+func domainAllowed(patterns []string, domain string) bool {
+	host, port := splitHostPort(domain)
+	for _, pattern := range patterns {
+		patternHost, patternPort := splitHostPort(pattern)
+		if patternHost == "" || patternPort != port {
+			continue
+		}
+		if patternHost == "*" || patternHost == host {
+			return true
+		}
+		if strings.HasPrefix(patternHost, "*.") && strings.HasSuffix(host, patternHost[1:]) && len(host) > len(patternHost)-1 {
+			return true
+		}
+	}
+	return false
+}
+
+func splitHostPort(domain string) (host, port string) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if h, p, err := net.SplitHostPort(domain); err == nil {
+		return h, p
+	}
+	return domain, ""
+}
+
+// checkDomain checks both the `domain` and the host of the `uri` of the SIWE message against the allowed domains
+func (a *Authenticator) checkDomain(message string) error {
+	m, err := signature.ParseMessage(message)
+	if err != nil {
+		return autherr.Wrap(autherr.CodeMalformed, "invalid message", err)
+	}
+
+	if !domainAllowed(a.allowedDomains, m.Domain) {
+		return autherr.New(autherr.CodeDomainNotAllowed, "domain not allowed: "+m.Domain)
+	}
+	uri, err := url.Parse(m.URI)
+	if err != nil {
+		return autherr.Wrap(autherr.CodeMalformed, "invalid message uri", err)
+	}
+	if !domainAllowed(a.allowedDomains, uri.Host) {
+		return autherr.New(autherr.CodeDomainNotAllowed, "domain not allowed: "+uri.Host)
+	}
+
+	return nil
+}
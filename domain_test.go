@@ -0,0 +1,47 @@
+package seeauth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Taoist-Labs/see-auth-go/common"
+)
+
+func Test_domainAllowed(t *testing.T) {
+	patterns := []string{"seedao.xyz", "*.seedao.xyz", "localhost:3000"}
+	tests := []struct {
+		name   string
+		domain string
+		want   bool
+	}{
+		{name: "exact", domain: "seedao.xyz", want: true},
+		{name: "exact case insensitive", domain: "SeeDAO.xyz", want: true},
+		{name: "subdomain", domain: "app.seedao.xyz", want: true},
+		{name: "nested subdomain", domain: "a.b.seedao.xyz", want: true},
+		{name: "port", domain: "localhost:3000", want: true},
+		{name: "port not match", domain: "localhost:3001", want: false},
+		{name: "port not allowed", domain: "app.seedao.xyz:8080", want: false},
+		{name: "no port", domain: "localhost", want: false},
+		{name: "suffix attack", domain: "evilseedao.xyz", want: false},
+		{name: "other domain", domain: "seedao.xyz.evil.com", want: false},
+		{name: "empty", domain: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := domainAllowed(patterns, tt.domain); got != tt.want {
+				t.Errorf("domainAllowed() = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_DomainNotAllowed(t *testing.T) {
+	issuer := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)))
+	seeAuth := issueSeeAuth(t, issuer)
+
+	a := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)), WithAllowedDomains("*.seedao.tech"))
+	_, err := a.SeeDAOAuth(recipient, seeAuth)
+	if !errors.Is(err, ErrDomainNotAllowed) {
+		t.Errorf("SeeDAOAuth() error = %v, want = %v", err, ErrDomainNotAllowed)
+	}
+}
@@ -0,0 +1,20 @@
+package seeauth
+
+import "github.com/Taoist-Labs/see-auth-go/autherr"
+
+// AuthError is returned by all verification paths, see package `autherr`
+type AuthError = autherr.AuthError
+
+// sentinel errors for `errors.Is`, an error matches a sentinel when their codes are equal
+var (
+	ErrMalformed         = autherr.ErrMalformed
+	ErrReplay            = autherr.ErrReplay
+	ErrStaleBlock        = autherr.ErrStaleBlock
+	ErrProofExpired      = autherr.ErrProofExpired
+	ErrRecipientMismatch = autherr.ErrRecipientMismatch
+	ErrAttesterMismatch  = autherr.ErrAttesterMismatch
+	ErrSignatureMismatch = autherr.ErrSignatureMismatch
+	ErrPayloadMismatch   = autherr.ErrPayloadMismatch
+	ErrDomainNotAllowed  = autherr.ErrDomainNotAllowed
+	ErrMessagePolicy     = autherr.ErrMessagePolicy
+)
@@ -0,0 +1,86 @@
+package seeauth
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/Taoist-Labs/see-auth-go/autherr"
+	"github.com/spruceid/siwe-go"
+)
+
+// A nonce must be alphanumeric to be put in a SIWE message, so the version prefix and the delimiter are letters.
+//
+//	version 1: `v1` + random(16) + `x` + block number + `x` + chain ID, e.g. `v1oNCEHm5jzQU2WvuBx19000000x1`
+//	legacy:    random(16) + block number, e.g. `oNCEHm5jzQU2WvuB19000000`
+const (
+	nonceVersionPrefix = "v1"
+	nonceDelimiter     = "x"
+	nonceRandomLength  = 16
+)
+
+var (
+	nonceV1Regexp     = regexp.MustCompile("^" + nonceVersionPrefix + "([a-zA-Z0-9]{16})" + nonceDelimiter + "([0-9]+)" + nonceDelimiter + "([0-9]+)$")
+	nonceLegacyRegexp = regexp.MustCompile("^([a-zA-Z0-9]{16})([0-9]+)$")
+)
+
+// errors of `ParseNonce`, they are wrapped in an AuthError with `autherr.CodeMalformed`
+var (
+	ErrNonceTooShort    = errors.New("nonce too short")
+	ErrNonceMalformed   = errors.New("nonce malformed")
+	ErrNonceBlockNumber = errors.New("nonce block number out of range")
+)
+
+// Nonce is the nonce of a SIWE message, it binds the message to a recent block
+type Nonce struct {
+	Version     int    // 0 for legacy nonces
+	Random      string // 16 alphanumeric characters
+	BlockNumber int64  // 0 if the block number was unavailable when the nonce was generated
+	ChainID     int64  // 0 for legacy nonces, the chain is unknown
+}
+
+// NewNonce creates a version 1 Nonce with a random part
+func NewNonce(blockNumber, chainID int64) Nonce {
+	return Nonce{
+		Version:     1,
+		Random:      siwe.GenerateNonce(),
+		BlockNumber: blockNumber,
+		ChainID:     chainID,
+	}
+}
+
+func (n Nonce) String() string {
+	if n.Version == 0 {
+		return n.Random + strconv.FormatInt(n.BlockNumber, 10)
+	}
+	return nonceVersionPrefix + n.Random + nonceDelimiter + strconv.FormatInt(n.BlockNumber, 10) + nonceDelimiter + strconv.FormatInt(n.ChainID, 10)
+}
+
+// ParseNonce parses a version 1 or a legacy nonce
+func ParseNonce(s string) (Nonce, error) {
+	if len(s) <= nonceRandomLength {
+		return Nonce{}, autherr.Wrap(autherr.CodeMalformed, "invalid nonce", ErrNonceTooShort)
+	}
+
+	if m := nonceV1Regexp.FindStringSubmatch(s); m != nil {
+		blockNumber, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return Nonce{}, autherr.Wrap(autherr.CodeMalformed, "invalid nonce", ErrNonceBlockNumber)
+		}
+		chainID, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			return Nonce{}, autherr.Wrap(autherr.CodeMalformed, "invalid nonce", ErrNonceMalformed)
+		}
+		return Nonce{Version: 1, Random: m[1], BlockNumber: blockNumber, ChainID: chainID}, nil
+	}
+
+	if m := nonceLegacyRegexp.FindStringSubmatch(s); m != nil {
+		blockNumber, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return Nonce{}, autherr.Wrap(autherr.CodeMalformed, "invalid nonce", ErrNonceBlockNumber)
+		}
+		return Nonce{Version: 0, Random: m[1], BlockNumber: blockNumber}, nil
+	}
+
+	return Nonce{}, autherr.Wrap(autherr.CodeMalformed, "invalid nonce", ErrNonceMalformed)
+}
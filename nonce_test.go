@@ -0,0 +1,138 @@
+package seeauth
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Taoist-Labs/see-auth-go/common"
+)
+
+func TestParseNonce(t *testing.T) {
+	tests := []struct {
+		name    string
+		nonce   string
+		want    Nonce
+		wantErr error
+	}{
+		{
+			name:  "version 1",
+			nonce: "v1oNCEHm5jzQU2WvuBx19000000x1",
+			want:  Nonce{Version: 1, Random: "oNCEHm5jzQU2WvuB", BlockNumber: 19000000, ChainID: 1},
+		},
+		{
+			name:  "version 1 random part with delimiter",
+			nonce: "v1xxxxxxxxxxxxxxxxx0x137",
+			want:  Nonce{Version: 1, Random: "xxxxxxxxxxxxxxxx", BlockNumber: 0, ChainID: 137},
+		},
+		{
+			name:  "legacy",
+			nonce: "oNCEHm5jzQU2WvuB19000000",
+			want:  Nonce{Version: 0, Random: "oNCEHm5jzQU2WvuB", BlockNumber: 19000000},
+		},
+		{
+			name:  "legacy without block number",
+			nonce: "oNCEHm5jzQU2WvuB0",
+			want:  Nonce{Version: 0, Random: "oNCEHm5jzQU2WvuB", BlockNumber: 0},
+		},
+		{
+			name:    "too short",
+			nonce:   "oNCEHm5jzQU2",
+			wantErr: ErrNonceTooShort,
+		},
+		{
+			name:    "legacy malformed suffix",
+			nonce:   "oNCEHm5jzQU2WvuB19000abc",
+			wantErr: ErrNonceMalformed,
+		},
+		{
+			name:    "version 1 missing chain ID",
+			nonce:   "v1oNCEHm5jzQU2WvuBx19000000",
+			wantErr: ErrNonceMalformed,
+		},
+		{
+			name:    "block number out of range",
+			nonce:   "oNCEHm5jzQU2WvuB99999999999999999999",
+			wantErr: ErrNonceBlockNumber,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNonce(tt.nonce)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseNonce() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !errors.Is(err, ErrMalformed) {
+					t.Errorf("ParseNonce() error = %v, want = %v", err, ErrMalformed)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNonce() got = %+v, want = %+v", got, tt.want)
+			}
+			if got.String() != tt.nonce {
+				t.Errorf("Nonce.String() = %v, want = %v", got.String(), tt.nonce)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_GenerateNonce(t *testing.T) {
+	a := NewAuthenticator(WithBlockNumberSource(common.StaticBlockNumber(19000000)), WithChainID(11155111))
+
+	got, err := ParseNonce(a.GenerateNonce())
+	if err != nil {
+		t.Fatalf("ParseNonce() error = %v", err)
+	}
+	if got.Version != 1 || got.BlockNumber != 19000000 || got.ChainID != 11155111 {
+		t.Errorf("ParseNonce() got = %+v", got)
+	}
+}
+
+func TestAuthenticator_checkBlockNumber(t *testing.T) {
+	a := NewAuthenticator(WithBlockNumberSource(common.StaticBlockNumber(19000010)))
+
+	tests := []struct {
+		name    string
+		nonce   string
+		wantErr error
+	}{
+		{name: "fresh", nonce: "v1oNCEHm5jzQU2WvuBx19000008x1", wantErr: nil},
+		{name: "stale", nonce: "v1oNCEHm5jzQU2WvuBx19000000x1", wantErr: ErrStaleBlock},
+		{name: "legacy fresh", nonce: "oNCEHm5jzQU2WvuB19000008", wantErr: nil},
+		{name: "legacy stale", nonce: "oNCEHm5jzQU2WvuB19000000", wantErr: ErrStaleBlock},
+		{name: "no block number", nonce: "v1oNCEHm5jzQU2WvuBx0x1", wantErr: nil},
+		{name: "other chain", nonce: "v1oNCEHm5jzQU2WvuBx19000008x137", wantErr: ErrMalformed},
+		{name: "too short", nonce: "short", wantErr: ErrNonceTooShort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := a.checkBlockNumber(context.Background(), tt.nonce)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkBlockNumber() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_StaleBlockNumber(t *testing.T) {
+	// a stale height is still the best height known, it doesn't turn the freshness check off
+	stale := common.BlockNumberFunc(func(ctx context.Context) (int64, error) {
+		return 19000010, common.ErrBlockNumberStale
+	})
+	a := NewAuthenticator(WithBlockNumberSource(stale))
+
+	got, err := ParseNonce(a.GenerateNonce())
+	if err != nil {
+		t.Fatalf("ParseNonce() error = %v", err)
+	}
+	if got.BlockNumber != 19000010 {
+		t.Errorf("ParseNonce() block number = %v, want = 19000010", got.BlockNumber)
+	}
+	if err = a.checkBlockNumber(context.Background(), "v1oNCEHm5jzQU2WvuBx19000000x1"); !errors.Is(err, ErrStaleBlock) {
+		t.Errorf("checkBlockNumber() error = %v, wantErr = %v", err, ErrStaleBlock)
+	}
+}
@@ -0,0 +1,38 @@
+package proof
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Attester is a trusted signer of proofs, it is trusted between `NotBefore` and `NotAfter`
+type Attester struct {
+	Address   string
+	NotBefore time.Time // zero means no lower bound
+	NotAfter  time.Time // zero means no upper bound
+	Label     string    // e.g. "seedao-os-2024", only for reporting
+}
+
+// ValidAt reports whether the attester is trusted at `t`
+func (a *Attester) ValidAt(t time.Time) bool {
+	return (a.NotBefore.IsZero() || !t.Before(a.NotBefore)) && (a.NotAfter.IsZero() || !t.After(a.NotAfter))
+}
+
+// AttesterSet is a set of trusted attesters.
+// During a key rotation, both the old and the new key are in the set with overlapping validity windows.
+type AttesterSet []Attester
+
+// Match returns the attester with `address` which is trusted at `t`
+func (s AttesterSet) Match(address string, t time.Time) (*Attester, bool) {
+	if !common.IsHexAddress(address) {
+		return nil, false
+	}
+	addr := common.HexToAddress(address)
+	for i := range s {
+		if common.HexToAddress(s[i].Address) == addr && s[i].ValidAt(t) {
+			return &s[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,98 @@
+package proof
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Taoist-Labs/see-auth-go/autherr"
+)
+
+func TestAttesterSet_Match(t *testing.T) {
+	rotation := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	attesters := AttesterSet{
+		{Address: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", NotAfter: rotation.Add(24 * time.Hour), Label: "old"},
+		{Address: attester, NotBefore: rotation, Label: "new"},
+	}
+
+	tests := []struct {
+		name      string
+		address   string
+		at        time.Time
+		wantLabel string
+		wantOk    bool
+	}{
+		{name: "old key before rotation", address: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", at: rotation.Add(-time.Hour), wantLabel: "old", wantOk: true},
+		{name: "new key before rotation", address: attester, at: rotation.Add(-time.Hour), wantOk: false},
+		{name: "old key during overlap", address: "0x70997970c51812dc3a010c7d01b50e0d17dc79c8", at: rotation.Add(time.Hour), wantLabel: "old", wantOk: true},
+		{name: "new key during overlap", address: attester, at: rotation.Add(time.Hour), wantLabel: "new", wantOk: true},
+		{name: "old key after rotation", address: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", at: rotation.Add(48 * time.Hour), wantOk: false},
+		{name: "unknown key", address: "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC", at: rotation, wantOk: false},
+		{name: "invalid address", address: "0x1234", at: rotation, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := attesters.Match(tt.address, tt.at)
+			if ok != tt.wantOk {
+				t.Errorf("Match() ok = %v, want = %v", ok, tt.wantOk)
+				return
+			}
+			if ok && got.Label != tt.wantLabel {
+				t.Errorf("Match() label = %v, want = %v", got.Label, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestVerifyAttesters(t *testing.T) {
+	now := time.Now()
+	proof, err := SignAt(now, recipient, proofLifetime, schemaData, privateKey)
+	if err != nil {
+		t.Fatalf("SignAt() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		attesters AttesterSet
+		wantLabel string
+		wantErr   error
+	}{
+		{
+			name: "ok",
+			attesters: AttesterSet{
+				{Address: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", Label: "old"},
+				{Address: attester, NotBefore: now.Add(-time.Hour), Label: "new"},
+			},
+			wantLabel: "new",
+		},
+		{
+			name:      "attester not valid yet",
+			attesters: AttesterSet{{Address: attester, NotBefore: now.Add(time.Hour), Label: "new"}},
+			wantErr:   autherr.ErrAttesterMismatch,
+		},
+		{
+			name:      "attester not trusted",
+			attesters: AttesterSet{{Address: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", Label: "old"}},
+			wantErr:   autherr.ErrAttesterMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotSchemaData, err := VerifyAttesters(now, tt.attesters, recipient, proof)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyAttesters() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Label != tt.wantLabel {
+				t.Errorf("VerifyAttesters() label = %v, want = %v", got.Label, tt.wantLabel)
+			}
+			if !reflect.DeepEqual(gotSchemaData, schemaData) {
+				t.Errorf("VerifyAttesters() gotSchemaData = %v, want = %v", gotSchemaData, schemaData)
+			}
+		})
+	}
+}
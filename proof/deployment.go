@@ -0,0 +1,177 @@
+package proof
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Deployment is an EAS deployment, it decides the EIP-712 domain and the schema of proofs
+type Deployment struct {
+	Name               string
+	ChainID            int64
+	EASContractAddress string
+	EASVersion         string // returned by `EAS.version()`, it is the `version` of the EIP-712 domain, signatures of another version don't verify
+	SchemaUID          string
+	NonEVMSchemaUID    string // the schema for wallets which are not EVM addresses (e.g. Solana), they are not supported if empty
+}
+
+const (
+	// seeAuthSchemaUID is the UID of `string signature,address wallet,string vendor` as revocable without resolver,
+	// it's computed by `offchain.ComputeSchemaUID` and NOT confirmed to be registered by the SchemaRegistry of the deployments.
+	// Off-chain attestations don't require it, but EASScan can't show the data until it's registered
+	seeAuthSchemaUID = "0x57da98d8f7e4e1f47ac9d0de2f2d408dc93d0639c2d713903b47b036c3fd10f7"
+	// seeAuthNonEVMSchemaUID is the UID of `string signature,string wallet,string vendor` as revocable without resolver,
+	// it's computed and NOT confirmed to be registered either
+	seeAuthNonEVMSchemaUID = "0xb375d491164124f9e98e6a06f3975ea4245df95a9e04514e9269e3c9597025a2"
+)
+
+// built-in deployments.
+// Except PolygonMumbai, of which proofs have been issued by this SDK, the EAS versions are taken from the EAS docs and NOT confirmed
+// by calling `EAS.version()` of the contracts, and the schema UIDs are NOT confirmed to be registered.
+// Confirm both before issuing proofs on them
+var (
+	Mainnet = Deployment{
+		Name:               "mainnet",
+		ChainID:            1,
+		EASContractAddress: "0xA1207F3BBa224E2c9c3c6D5aF63D0eb1582Ce587",
+		EASVersion:         "0.26",
+		SchemaUID:          seeAuthSchemaUID,
+		NonEVMSchemaUID:    seeAuthNonEVMSchemaUID,
+	}
+	Sepolia = Deployment{
+		Name:               "sepolia",
+		ChainID:            11155111,
+		EASContractAddress: "0xC2679fBD37d54388Ce493F1DB75320D236e1815e",
+		EASVersion:         "0.26",
+		SchemaUID:          seeAuthSchemaUID,
+		NonEVMSchemaUID:    seeAuthNonEVMSchemaUID,
+	}
+	Optimism = Deployment{
+		Name:               "optimism",
+		ChainID:            10,
+		EASContractAddress: "0x4200000000000000000000000000000000000021",
+		EASVersion:         "1.0.1",
+		SchemaUID:          seeAuthSchemaUID,
+		NonEVMSchemaUID:    seeAuthNonEVMSchemaUID,
+	}
+	Arbitrum = Deployment{
+		Name:               "arbitrum",
+		ChainID:            42161,
+		EASContractAddress: "0xbD75f629A22Dc1ceD33dDA0b68c546A1c035c458",
+		EASVersion:         "0.26",
+		SchemaUID:          seeAuthSchemaUID,
+		NonEVMSchemaUID:    seeAuthNonEVMSchemaUID,
+	}
+	Base = Deployment{
+		Name:               "base",
+		ChainID:            8453,
+		EASContractAddress: "0x4200000000000000000000000000000000000021",
+		EASVersion:         "1.0.1",
+		SchemaUID:          seeAuthSchemaUID,
+		NonEVMSchemaUID:    seeAuthNonEVMSchemaUID,
+	}
+	Polygon = Deployment{
+		Name:               "polygon",
+		ChainID:            137,
+		EASContractAddress: "0x5E634ef5355f45A855d02D66eCD687b1502AF790",
+		EASVersion:         "1.3.0",
+		SchemaUID:          seeAuthSchemaUID,
+		NonEVMSchemaUID:    seeAuthNonEVMSchemaUID,
+	}
+	PolygonAmoy = Deployment{
+		Name:               "polygon-amoy",
+		ChainID:            80002,
+		EASContractAddress: "0xb101275a60d8bfb14529C421899aD7CA1Ae5B5Fc",
+		EASVersion:         "1.3.0",
+		SchemaUID:          seeAuthSchemaUID,
+		NonEVMSchemaUID:    seeAuthNonEVMSchemaUID,
+	}
+	// PolygonMumbai has been shut down, but proofs are still issued on it by default, see `DefaultDeployment`.
+	// The non-EVM schema can't be registered on it anymore, so EASScan can't show the data of proofs of non-EVM wallets
+	PolygonMumbai = Deployment{
+		Name:               "polygon-mumbai",
+		ChainID:            80001,
+		EASContractAddress: "0xaEF4103A04090071165F78D45D83A0C0782c2B2a",
+		EASVersion:         "1.2.0",
+		SchemaUID:          "0x32275eb98dcb8f82848adef9fa52311cc9e83bc6fdb34c5f46ac4b8d957ad3d9",
+		NonEVMSchemaUID:    seeAuthNonEVMSchemaUID,
+	}
+
+	// DefaultDeployment is used by `Sign`, proofs are issued on it.
+	// It stays PolygonMumbai, which consumers of the proofs check, until the schemas are registered on another deployment
+	DefaultDeployment = PolygonMumbai
+)
+
+// DefaultDeployments returns the deployments accepted by `Verify` and by a `Verifier` without deployments, i.e. `DefaultDeployment`,
+// and PolygonMumbai if the default has been moved, since proofs were issued on it before
+func DefaultDeployments() []Deployment {
+	if DefaultDeployment.ChainID == PolygonMumbai.ChainID {
+		return []Deployment{DefaultDeployment}
+	}
+	return []Deployment{DefaultDeployment, PolygonMumbai}
+}
+
+var (
+	deploymentsMu sync.RWMutex
+	deployments   = map[int64]Deployment{}
+)
+
+func init() {
+	for _, d := range []Deployment{Mainnet, Sepolia, Optimism, Arbitrum, Base, Polygon, PolygonAmoy, PolygonMumbai} {
+		RegisterDeployment(d)
+	}
+}
+
+// RegisterDeployment adds a deployment to the registry, it replaces the deployment of the same chain
+func RegisterDeployment(d Deployment) {
+	deploymentsMu.Lock()
+	defer deploymentsMu.Unlock()
+
+	deployments[d.ChainID] = d
+}
+
+// LookupDeployment returns the registered deployment of `chainID`
+func LookupDeployment(chainID int64) (Deployment, bool) {
+	deploymentsMu.RLock()
+	defer deploymentsMu.RUnlock()
+
+	d, ok := deployments[chainID]
+	return d, ok
+}
+
+// Deployments returns all registered deployments ordered by chain ID
+func Deployments() []Deployment {
+	deploymentsMu.RLock()
+	defer deploymentsMu.RUnlock()
+
+	ds := make([]Deployment, 0, len(deployments))
+	for _, d := range deployments {
+		ds = append(ds, d)
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i].ChainID < ds[j].ChainID })
+	return ds
+}
+
+// TypedDataDomain returns the EIP-712 domain of off-chain attestations on the deployment
+func (d *Deployment) TypedDataDomain() apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              "EAS Attestation",
+		Version:           d.EASVersion,
+		ChainId:           math.NewHexOrDecimal256(d.ChainID),
+		VerifyingContract: d.EASContractAddress,
+	}
+}
+
+// matchDomain reports whether `domain` is the domain of the deployment, the address is compared case-insensitively
+func (d *Deployment) matchDomain(domain apitypes.TypedDataDomain) bool {
+	return domain.Name == "EAS Attestation" &&
+		domain.Version == d.EASVersion &&
+		domain.ChainId != nil && (*big.Int)(domain.ChainId).Cmp(big.NewInt(d.ChainID)) == 0 &&
+		common.IsHexAddress(domain.VerifyingContract) &&
+		common.HexToAddress(domain.VerifyingContract) == common.HexToAddress(d.EASContractAddress)
+}
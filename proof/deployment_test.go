@@ -0,0 +1,117 @@
+package proof
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Taoist-Labs/see-auth-go/autherr"
+	"github.com/Taoist-Labs/see-auth-go/proof/offchain"
+)
+
+func TestLookupDeployment(t *testing.T) {
+	tests := []struct {
+		name     string
+		chainID  int64
+		wantName string
+		wantOk   bool
+	}{
+		{name: "mainnet", chainID: 1, wantName: Mainnet.Name, wantOk: true},
+		{name: "sepolia", chainID: 11155111, wantName: Sepolia.Name, wantOk: true},
+		{name: "optimism", chainID: 10, wantName: Optimism.Name, wantOk: true},
+		{name: "arbitrum", chainID: 42161, wantName: Arbitrum.Name, wantOk: true},
+		{name: "base", chainID: 8453, wantName: Base.Name, wantOk: true},
+		{name: "polygon", chainID: 137, wantName: Polygon.Name, wantOk: true},
+		{name: "amoy", chainID: 80002, wantName: PolygonAmoy.Name, wantOk: true},
+		{name: "unknown", chainID: 12345, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LookupDeployment(tt.chainID)
+			if ok != tt.wantOk {
+				t.Errorf("LookupDeployment() ok = %v, want = %v", ok, tt.wantOk)
+				return
+			}
+			if ok && got.Name != tt.wantName {
+				t.Errorf("LookupDeployment() name = %v, want = %v", got.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	now := time.Now()
+	attesters := AttesterSet{{Address: attester}}
+	signer, err := offchain.NewHexKeySigner(privateKey)
+	if err != nil {
+		t.Fatalf("NewHexKeySigner() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		issuedOn    Deployment
+		deployments []Deployment
+		version     *offchain.Version
+		wantErr     bool
+	}{
+		{name: "default deployment", issuedOn: DefaultDeployment, deployments: nil, wantErr: false},
+		{name: "accepted deployment", issuedOn: Base, deployments: []Deployment{Mainnet, Base}, wantErr: false},
+		{name: "not accepted deployment", issuedOn: Sepolia, deployments: []Deployment{Mainnet, Base}, wantErr: true},
+		{name: "not accepted by default", issuedOn: Optimism, deployments: nil, wantErr: true},
+		{name: "unconfirmed deployment not accepted by default", issuedOn: Polygon, deployments: nil, wantErr: true},
+		{name: "version 0", issuedOn: DefaultDeployment, deployments: nil, version: versionPtr(offchain.Version0), wantErr: false},
+		{name: "version 2", issuedOn: DefaultDeployment, deployments: nil, version: versionPtr(offchain.Version2), wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof, err := (&Issuer{Deployment: tt.issuedOn, Version: tt.version}).Sign(context.Background(), now, recipient, proofLifetime, schemaData, signer)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			got, err := (&Verifier{Attesters: attesters, Deployments: tt.deployments}).Verify(now, recipient, proof)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if autherr.CodeOf(err) != autherr.CodeMalformed {
+					t.Errorf("Verify() error code = %v, want = %v", autherr.CodeOf(err), autherr.CodeMalformed)
+				}
+				return
+			}
+			if got.Deployment.ChainID != tt.issuedOn.ChainID {
+				t.Errorf("Verify() chain = %v, want = %v", got.Deployment.ChainID, tt.issuedOn.ChainID)
+			}
+			wantVersion := offchain.Version1
+			if tt.version != nil {
+				wantVersion = *tt.version
+			}
+			if got.Version != wantVersion {
+				t.Errorf("Verify() version = %v, want = %v", got.Version, wantVersion)
+			}
+			if got.SchemaData.Wallet != schemaData.Wallet {
+				t.Errorf("Verify() wallet = %v, want = %v", got.SchemaData.Wallet, schemaData.Wallet)
+			}
+		})
+	}
+}
+
+func versionPtr(v offchain.Version) *offchain.Version {
+	return &v
+}
+
+func TestDefaultDeployments(t *testing.T) {
+	if got := DefaultDeployments(); len(got) != 1 || got[0].ChainID != PolygonMumbai.ChainID {
+		t.Errorf("DefaultDeployments() = %v, want = [%v]", got, PolygonMumbai.Name)
+	}
+
+	// proofs issued on PolygonMumbai are still accepted once the default is moved
+	defaultDeployment := DefaultDeployment
+	DefaultDeployment = Polygon
+	defer func() { DefaultDeployment = defaultDeployment }()
+	got := DefaultDeployments()
+	if len(got) != 2 || got[0].ChainID != Polygon.ChainID || got[1].ChainID != PolygonMumbai.ChainID {
+		t.Errorf("DefaultDeployments() = %v, want = [%v %v]", got, Polygon.Name, PolygonMumbai.Name)
+	}
+}
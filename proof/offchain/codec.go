@@ -0,0 +1,487 @@
+package offchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Marshal encodes a struct to the schema data, fields are encoded in order by their `eas` tags, e.g.
+//
+//	type Member struct {
+//		Wallet common.Address `eas:"address wallet"`
+//		Roles  []string       `eas:"string[] roles"`
+//		Tier   uint8          `eas:"uint8 tier"`
+//		Badge  Badge          `eas:"tuple badge"` // a struct of which the fields have `eas` tags
+//	}
+//
+// Fields without `eas` tag or with `eas:"-"` are skipped. Go integers of any width and `*big.Int` are accepted for
+// `uint<M>` and `int<M>` if the value is in range, a hex string is accepted for `address`, and a slice of the exact length for `bytes<M>`
+func Marshal(v any) (string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return "", fmt.Errorf("eas: Marshal of non-struct %T", v)
+	}
+	fields, err := easFields(rv.Type())
+	if err != nil {
+		return "", err
+	}
+
+	arguments := make(abi.Arguments, len(fields))
+	values := make([]any, len(fields))
+	for i, f := range fields {
+		arguments[i] = abi.Argument{Name: f.name, Type: f.typ}
+		value, err := toABI(f.typ, rv.Field(f.index))
+		if err != nil {
+			return "", fmt.Errorf("eas: field %s: %w", f.name, err)
+		}
+		values[i] = value.Interface()
+	}
+	b, err := arguments.Pack(values...)
+	if err != nil {
+		return "", fmt.Errorf("eas: %w", err)
+	}
+	return hexutil.Encode(b), nil
+}
+
+// Unmarshal decodes the schema data to the struct pointed by `v`, see `Marshal` for the `eas` tags.
+// An `address` is decoded to `common.Address`, `[20]byte` or the checksum hex string
+func Unmarshal(data string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("eas: Unmarshal to non-struct pointer %T", v)
+	}
+	rv = rv.Elem()
+	fields, err := easFields(rv.Type())
+	if err != nil {
+		return err
+	}
+	b, err := hexutil.Decode(data)
+	if err != nil {
+		return fmt.Errorf("eas: %w", err)
+	}
+
+	arguments := make(abi.Arguments, len(fields))
+	for i, f := range fields {
+		arguments[i] = abi.Argument{Name: f.name, Type: f.typ}
+	}
+	values, err := arguments.Unpack(b)
+	if err != nil {
+		return fmt.Errorf("eas: %w", err)
+	}
+	for i, f := range fields {
+		if err = fromABI(f.typ, reflect.ValueOf(values[i]), rv.Field(f.index)); err != nil {
+			return fmt.Errorf("eas: field %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// easField is a struct field with an `eas` tag
+type easField struct {
+	name  string
+	typ   abi.Type
+	index int
+}
+
+func easFields(typ reflect.Type) ([]easField, error) {
+	var fields []easField
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag, ok := sf.Tag.Lookup("eas")
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+		m, err := argumentMarshaling(tag, sf.Type)
+		if err != nil {
+			return nil, fmt.Errorf("eas: field %s: %w", sf.Name, err)
+		}
+		t, err := abi.NewType(m.Type, "", m.Components)
+		if err != nil {
+			return nil, fmt.Errorf("eas: field %s: %w", sf.Name, err)
+		}
+		fields = append(fields, easField{name: m.Name, typ: t, index: i})
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("eas: %s has no field with eas tag", typ)
+	}
+	return fields, nil
+}
+
+// argumentMarshaling parses the `eas` tag `<type> <name>`, the components of a tuple are the `eas` fields of the struct type
+func argumentMarshaling(tag string, typ reflect.Type) (abi.ArgumentMarshaling, error) {
+	parts := strings.Fields(tag)
+	if len(parts) != 2 {
+		return abi.ArgumentMarshaling{}, fmt.Errorf("invalid eas tag %q, expect `<type> <name>`", tag)
+	}
+	m := abi.ArgumentMarshaling{Name: parts[1], Type: parts[0]}
+	if !strings.HasPrefix(m.Type, "tuple") {
+		return m, nil
+	}
+
+	elem := typ
+	for elem.Kind() == reflect.Pointer || elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return abi.ArgumentMarshaling{}, fmt.Errorf("tuple of non-struct %s", typ)
+	}
+	for i := 0; i < elem.NumField(); i++ {
+		sf := elem.Field(i)
+		tag, ok := sf.Tag.Lookup("eas")
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+		c, err := argumentMarshaling(tag, sf.Type)
+		if err != nil {
+			return abi.ArgumentMarshaling{}, fmt.Errorf("field %s: %w", sf.Name, err)
+		}
+		m.Components = append(m.Components, c)
+	}
+	if len(m.Components) == 0 {
+		return abi.ArgumentMarshaling{}, fmt.Errorf("%s has no field with eas tag", elem)
+	}
+	return m, nil
+}
+
+var (
+	bigIntType  = reflect.TypeOf((*big.Int)(nil))
+	addressType = reflect.TypeOf(common.Address{})
+)
+
+// toABI converts `v` to the Go type of the ABI type `t`
+func toABI(t abi.Type, v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer && v.Type() != bigIntType {
+		if v.IsNil() && v.Kind() == reflect.Interface {
+			return reflect.Value{}, errors.New("nil value")
+		}
+		if v.IsNil() {
+			return reflect.Value{}, errors.New("nil pointer")
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() { // a nil `any`, e.g. a nil value of `EncodeFields`
+		return reflect.Value{}, errors.New("nil value")
+	}
+	target := t.GetType()
+
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := toBig(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if err = checkRange(t, n); err != nil {
+			return reflect.Value{}, err
+		}
+		if target == bigIntType {
+			return reflect.ValueOf(n), nil
+		}
+		out := reflect.New(target).Elem()
+		if t.T == abi.IntTy {
+			out.SetInt(n.Int64())
+		} else {
+			out.SetUint(n.Uint64())
+		}
+		return out, nil
+	case abi.BoolTy:
+		if v.Kind() != reflect.Bool {
+			return reflect.Value{}, fmt.Errorf("can't use %s as bool", v.Type())
+		}
+		return reflect.ValueOf(v.Bool()), nil
+	case abi.StringTy:
+		if v.Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("can't use %s as string", v.Type())
+		}
+		return reflect.ValueOf(v.String()), nil
+	case abi.AddressTy:
+		switch {
+		case v.Kind() == reflect.String:
+			if !common.IsHexAddress(v.String()) {
+				return reflect.Value{}, fmt.Errorf("invalid address %q", v.String())
+			}
+			return reflect.ValueOf(common.HexToAddress(v.String())), nil
+		case v.Type().ConvertibleTo(addressType) && v.Kind() == reflect.Array:
+			return v.Convert(addressType), nil
+		default:
+			return reflect.Value{}, fmt.Errorf("can't use %s as address", v.Type())
+		}
+	case abi.BytesTy:
+		if !isByteSlice(v) {
+			return reflect.Value{}, fmt.Errorf("can't use %s as bytes", v.Type())
+		}
+		return reflect.ValueOf(v.Bytes()), nil
+	case abi.FixedBytesTy:
+		if (v.Kind() != reflect.Array && !isByteSlice(v)) || (v.Kind() == reflect.Array && v.Type().Elem().Kind() != reflect.Uint8) {
+			return reflect.Value{}, fmt.Errorf("can't use %s as bytes%d", v.Type(), t.Size)
+		}
+		if v.Len() != t.Size {
+			return reflect.Value{}, fmt.Errorf("%d bytes out of range of bytes%d", v.Len(), t.Size)
+		}
+		out := reflect.New(target).Elem()
+		reflect.Copy(out, v)
+		return out, nil
+	case abi.SliceTy, abi.ArrayTy:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return reflect.Value{}, fmt.Errorf("can't use %s as %s", v.Type(), t)
+		}
+		var out reflect.Value
+		if t.T == abi.SliceTy {
+			out = reflect.MakeSlice(target, v.Len(), v.Len())
+		} else {
+			if v.Len() != t.Size {
+				return reflect.Value{}, fmt.Errorf("%d elements, expect %d", v.Len(), t.Size)
+			}
+			out = reflect.New(target).Elem()
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := toABI(*t.Elem, v.Index(i))
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("[%d]: %w", i, err)
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, nil
+	case abi.TupleTy:
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("can't use %s as tuple", v.Type())
+		}
+		fields, err := easFields(v.Type())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(target).Elem()
+		for i, f := range fields {
+			elem, err := toABI(*t.TupleElems[i], v.Field(f.index))
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%s: %w", f.name, err)
+			}
+			out.Field(i).Set(elem)
+		}
+		return out, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// fromABI sets `v` decoded as the ABI type `t` to `dst`
+func fromABI(t abi.Type, v reflect.Value, dst reflect.Value) error {
+	if dst.Kind() == reflect.Pointer && dst.Type() != bigIntType {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return fromABI(t, v, dst.Elem())
+	}
+
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := toBig(v)
+		if err != nil {
+			return err
+		}
+		switch {
+		case dst.Type() == bigIntType:
+			dst.Set(reflect.ValueOf(n))
+		case dst.Type() == bigIntType.Elem():
+			dst.Set(reflect.ValueOf(*n))
+		case isInt(dst.Kind()):
+			if !n.IsInt64() || dst.OverflowInt(n.Int64()) {
+				return fmt.Errorf("%s out of range of %s", n, dst.Type())
+			}
+			dst.SetInt(n.Int64())
+		case isUint(dst.Kind()):
+			if !n.IsUint64() || dst.OverflowUint(n.Uint64()) {
+				return fmt.Errorf("%s out of range of %s", n, dst.Type())
+			}
+			dst.SetUint(n.Uint64())
+		default:
+			return fmt.Errorf("can't set %s to %s", t, dst.Type())
+		}
+		return nil
+	case abi.AddressTy:
+		address := v.Interface().(common.Address)
+		switch {
+		case dst.Kind() == reflect.String:
+			dst.SetString(address.Hex())
+		case addressType.ConvertibleTo(dst.Type()) && dst.Kind() == reflect.Array:
+			dst.Set(v.Convert(dst.Type()))
+		default:
+			return fmt.Errorf("can't set address to %s", dst.Type())
+		}
+		return nil
+	case abi.FixedBytesTy:
+		switch {
+		case isByteSlice(dst):
+			b := reflect.MakeSlice(dst.Type(), t.Size, t.Size)
+			reflect.Copy(b, v)
+			dst.Set(b)
+		case dst.Kind() == reflect.Array && dst.Type().Elem().Kind() == reflect.Uint8 && dst.Len() == t.Size:
+			reflect.Copy(dst, v)
+		default:
+			return fmt.Errorf("can't set bytes%d to %s", t.Size, dst.Type())
+		}
+		return nil
+	case abi.SliceTy, abi.ArrayTy:
+		switch {
+		case dst.Kind() == reflect.Slice:
+			dst.Set(reflect.MakeSlice(dst.Type(), v.Len(), v.Len()))
+		case dst.Kind() == reflect.Array && dst.Len() == v.Len():
+		default:
+			return fmt.Errorf("can't set %s to %s", t, dst.Type())
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := fromABI(*t.Elem, v.Index(i), dst.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		return nil
+	case abi.TupleTy:
+		if dst.Kind() != reflect.Struct {
+			return fmt.Errorf("can't set tuple to %s", dst.Type())
+		}
+		fields, err := easFields(dst.Type())
+		if err != nil {
+			return err
+		}
+		if len(fields) != len(t.TupleElems) {
+			return fmt.Errorf("%d fields of tuple, %s has %d", len(t.TupleElems), dst.Type(), len(fields))
+		}
+		for i, f := range fields {
+			if err := fromABI(*t.TupleElems[i], v.Field(i), dst.Field(f.index)); err != nil {
+				return fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+		return nil
+	default: // bool, string and bytes
+		if !v.Type().AssignableTo(dst.Type()) {
+			if !v.Type().ConvertibleTo(dst.Type()) || v.Kind() != dst.Kind() {
+				return fmt.Errorf("can't set %s to %s", t, dst.Type())
+			}
+			v = v.Convert(dst.Type())
+		}
+		dst.Set(v)
+		return nil
+	}
+}
+
+// toBig converts an integer or a `*big.Int` to `*big.Int`
+func toBig(v reflect.Value) (*big.Int, error) {
+	switch {
+	case v.Type() == bigIntType:
+		if v.IsNil() {
+			return nil, errors.New("nil *big.Int")
+		}
+		return new(big.Int).Set(v.Interface().(*big.Int)), nil
+	case v.Type() == bigIntType.Elem():
+		n := v.Interface().(big.Int)
+		return new(big.Int).Set(&n), nil
+	case isInt(v.Kind()):
+		return big.NewInt(v.Int()), nil
+	case isUint(v.Kind()):
+		return new(big.Int).SetUint64(v.Uint()), nil
+	default:
+		return nil, fmt.Errorf("can't use %s as integer", v.Type())
+	}
+}
+
+// checkRange checks `n` fits in the integer type `t`
+func checkRange(t abi.Type, n *big.Int) error {
+	var min, max *big.Int
+	if t.T == abi.UintTy {
+		min = new(big.Int)
+		max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(t.Size)), big.NewInt(1))
+	} else {
+		max = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1)), big.NewInt(1))
+		min = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1)))
+	}
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		return fmt.Errorf("%s out of range of %s", n, t)
+	}
+	return nil
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isByteSlice(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8
+}
+
+// EncodeFields encodes `values` as the schema fields, each value is converted as `Marshal` does
+func EncodeFields(fields []SchemaField, values []any) (string, error) {
+	if len(fields) != len(values) {
+		return "", errors.New("eas: length of fields and values do not match")
+	}
+	arguments, err := fieldArguments(fields)
+	if err != nil {
+		return "", err
+	}
+	abiValues := make([]any, len(values))
+	for i, v := range values {
+		value, err := toABI(arguments[i].Type, reflect.ValueOf(v))
+		if err != nil {
+			return "", fmt.Errorf("eas: field %s: %w", fields[i].Name, err)
+		}
+		abiValues[i] = value.Interface()
+	}
+	b, err := arguments.Pack(abiValues...)
+	if err != nil {
+		return "", fmt.Errorf("eas: %w", err)
+	}
+	return hexutil.Encode(b), nil
+}
+
+// DecodeFields decodes the schema data of the fields to `dsts`, which are pointers, each value is converted as `Unmarshal` does
+func DecodeFields(fields []SchemaField, data string, dsts []any) error {
+	if len(fields) != len(dsts) {
+		return errors.New("eas: length of fields and destinations do not match")
+	}
+	arguments, err := fieldArguments(fields)
+	if err != nil {
+		return err
+	}
+	b, err := hexutil.Decode(data)
+	if err != nil {
+		return fmt.Errorf("eas: %w", err)
+	}
+	values, err := arguments.Unpack(b)
+	if err != nil {
+		return fmt.Errorf("eas: %w", err)
+	}
+	for i, dst := range dsts {
+		rv := reflect.ValueOf(dst)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return fmt.Errorf("eas: field %s: destination %T is not a pointer", fields[i].Name, dst)
+		}
+		if err = fromABI(arguments[i].Type, reflect.ValueOf(values[i]), rv.Elem()); err != nil {
+			return fmt.Errorf("eas: field %s: %w", fields[i].Name, err)
+		}
+	}
+	return nil
+}
+
+func fieldArguments(fields []SchemaField) (abi.Arguments, error) {
+	arguments := make(abi.Arguments, len(fields))
+	for i := range fields {
+		t, err := fields[i].ABIType()
+		if err != nil {
+			return nil, fmt.Errorf("eas: field %s: %w", fields[i].Name, err)
+		}
+		arguments[i] = abi.Argument{Name: fields[i].Name, Type: t}
+	}
+	return arguments, nil
+}
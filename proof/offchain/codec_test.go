@@ -0,0 +1,171 @@
+package offchain
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type badge struct {
+	ID    *big.Int `eas:"uint256 id"`
+	Level int8     `eas:"int8 level"`
+}
+
+type member struct {
+	Signature string         `eas:"string signature"`
+	Wallet    common.Address `eas:"address wallet"`
+	Vendor    string         `eas:"string vendor"`
+	Roles     []string       `eas:"string[] roles"`
+	Tier      uint8          `eas:"uint8 tier"`
+	Active    bool           `eas:"bool active"`
+	Avatar    []byte         `eas:"bytes avatar"`
+	Ref       [32]byte       `eas:"bytes32 ref"`
+	Scores    [2]uint64      `eas:"uint64[2] scores"`
+	Badge     badge          `eas:"tuple badge"`
+	Badges    []badge        `eas:"tuple[] badges"`
+	Note      string         // no tag, skipped
+}
+
+func TestMarshal(t *testing.T) {
+	// `Marshal` of the SeeAuth schema is the same as `SchemaEncode`
+	seeAuth := struct {
+		Signature string `eas:"string signature"`
+		Wallet    string `eas:"address wallet"`
+		Vendor    string `eas:"string vendor"`
+	}{Signature: "0x1234", Wallet: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", Vendor: "os+"}
+	got, err := Marshal(&seeAuth)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want, _ := SchemaEncode([]string{"string", "address", "string"}, []any{seeAuth.Signature, common.HexToAddress(seeAuth.Wallet), seeAuth.Vendor})
+	if got != want {
+		t.Errorf("Marshal() got = %v, want = %v", got, want)
+	}
+
+	m := member{
+		Signature: "0x1234",
+		Wallet:    common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		Vendor:    "os+",
+		Roles:     []string{"builder", "node"},
+		Tier:      2,
+		Active:    true,
+		Avatar:    []byte("avatar"),
+		Ref:       [32]byte{1, 2, 3},
+		Scores:    [2]uint64{math.MaxUint64, 1},
+		Badge:     badge{ID: new(big.Int).Lsh(big.NewInt(1), 200), Level: -3},
+		Badges:    []badge{{ID: big.NewInt(1), Level: 1}, {ID: big.NewInt(2), Level: 127}},
+		Note:      "skipped",
+	}
+	data, err := Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded member
+	if err = Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	m.Note = ""
+	if !reflect.DeepEqual(decoded, m) {
+		t.Errorf("Unmarshal() got = %+v, want = %+v", decoded, m)
+	}
+
+	// the address is decoded to the checksum hex for a string field
+	var seeAuthDecoded struct {
+		Signature string `eas:"string signature"`
+		Wallet    string `eas:"address wallet"`
+		Vendor    string `eas:"string vendor"`
+	}
+	if err = Unmarshal(got, &seeAuthDecoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if seeAuthDecoded != seeAuth {
+		t.Errorf("Unmarshal() got = %+v, want = %+v", seeAuthDecoded, seeAuth)
+	}
+}
+
+func TestMarshal_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       any
+		wantErr string
+	}{
+		{name: "not struct", v: "os+", wantErr: "non-struct"},
+		{name: "no eas field", v: struct{ Name string }{}, wantErr: "no field with eas tag"},
+		{name: "invalid tag", v: struct {
+			Name string `eas:"string"`
+		}{}, wantErr: "invalid eas tag"},
+		{name: "invalid type", v: struct {
+			Name string `eas:"strin name"`
+		}{}, wantErr: "unsupported arg type: strin"},
+		{name: "uint out of range", v: struct {
+			Tier int `eas:"uint8 tier"`
+		}{Tier: 256}, wantErr: "256 out of range of uint8"},
+		{name: "negative uint", v: struct {
+			Tier int `eas:"uint8 tier"`
+		}{Tier: -1}, wantErr: "-1 out of range of uint8"},
+		{name: "int out of range", v: struct {
+			Level int `eas:"int8 level"`
+		}{Level: -129}, wantErr: "-129 out of range of int8"},
+		{name: "mismatched type", v: struct {
+			Tier string `eas:"uint8 tier"`
+		}{Tier: "2"}, wantErr: "can't use string as integer"},
+		{name: "invalid address", v: struct {
+			Wallet string `eas:"address wallet"`
+		}{Wallet: "alice.seedao"}, wantErr: "invalid address"},
+		{name: "bytesN length", v: struct {
+			Ref []byte `eas:"bytes32 ref"`
+		}{Ref: []byte{1}}, wantErr: "out of range of bytes32"},
+		{name: "array length", v: struct {
+			Scores []uint64 `eas:"uint64[2] scores"`
+		}{Scores: []uint64{1}}, wantErr: "1 elements, expect 2"},
+		{name: "nil interface", v: struct {
+			Tier any `eas:"uint8 tier"`
+		}{}, wantErr: "field tier: nil value"},
+		{name: "nil in interface", v: struct {
+			Tier any `eas:"uint8 tier"`
+		}{Tier: (*int)(nil)}, wantErr: "field tier: nil pointer"},
+		{name: "tuple of non-struct", v: struct {
+			Badge string `eas:"tuple badge"`
+		}{}, wantErr: "tuple of non-struct"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Marshal(tt.v)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Marshal() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_Error(t *testing.T) {
+	data, err := Marshal(struct {
+		Tier uint16 `eas:"uint16 tier"`
+	}{Tier: 300})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var narrow struct {
+		Tier uint8 `eas:"uint16 tier"`
+	}
+	if err = Unmarshal(data, &narrow); err == nil || !strings.Contains(err.Error(), "300 out of range of uint8") {
+		t.Errorf("Unmarshal() error = %v", err)
+	}
+	var mismatched struct {
+		Tier string `eas:"uint16 tier"`
+	}
+	if err = Unmarshal(data, &mismatched); err == nil {
+		t.Errorf("Unmarshal() to string error = nil")
+	}
+	if err = Unmarshal(data, narrow); err == nil {
+		t.Errorf("Unmarshal() to non-pointer error = nil")
+	}
+	if err = Unmarshal("0x12", &narrow); err == nil {
+		t.Errorf("Unmarshal() of short data error = nil")
+	}
+}
@@ -1,7 +1,7 @@
 package offchain
 
 import (
-	"crypto/ecdsa"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Taoist-Labs/see-auth-go/autherr"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -29,7 +30,13 @@ type (
 	}
 )
 
-func SignOffChainAttestation(privateKey *ecdsa.PrivateKey, typedData *apitypes.TypedData) (*Sig, error) {
+// SignOffChainAttestation signs `typedData` by `signer`
+func SignOffChainAttestation(ctx context.Context, signer Signer, typedData *apitypes.TypedData) (*Sig, error) {
+	version, err := VersionOf(typedData)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1 signHash
 	hash, err := signHash(typedData)
 	if err != nil {
@@ -39,15 +46,28 @@ func SignOffChainAttestation(privateKey *ecdsa.PrivateKey, typedData *apitypes.T
 	//fmt.Printf("sign-hash: %s\n", hexutil.Encode(hash))
 
 	// 2 signature
-	sig, err := crypto.Sign(hash, privateKey)
+	var sig []byte
+	if typedDataSigner, ok := signer.(TypedDataSigner); ok {
+		sig, err = typedDataSigner.SignTypedData(ctx, typedData)
+	} else {
+		sig, err = signer.SignHash(ctx, hash)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("generate signatrue error: %s", err)
+		return nil, fmt.Errorf("generate signatrue error: %w", err)
+	}
+	// the signer must be who it claims to be, a remote signer may sign by another account
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, fmt.Errorf("generate signatrue error: %w", err)
+	}
+	if crypto.PubkeyToAddress(*pubKey) != signer.Address() {
+		return nil, errors.New("generate signatrue error: signed by another account")
 	}
 	//fmt.Printf("sign-signature: %v\n", sig)
 	//fmt.Printf("sign-signature: %s\n", hexutil.Encode(sig))
 	r, s, v := convertToRSV(sig)
 
-	offChainUID := getOffChainUID(typedData.Message)
+	offChainUID := getOffChainUID(version, typedData.Message)
 
 	return &Sig{
 		TypedData: typedData,
@@ -61,77 +81,112 @@ func SignOffChainAttestation(privateKey *ecdsa.PrivateKey, typedData *apitypes.T
 }
 
 func VerifyOffChainAttestation(attester, recipient string, expectTypedData *apitypes.TypedData, sig *Sig) (bool, error) {
+	return VerifyOffChainAttestationAt(time.Now(), attester, recipient, expectTypedData, sig)
+}
+
+// VerifyOffChainAttestationAt is like `VerifyOffChainAttestation`, but the expiration time is checked against `now`
+func VerifyOffChainAttestationAt(now time.Time, attester, recipient string, expectTypedData *apitypes.TypedData, sig *Sig) (bool, error) {
+	if attester == "0x0000000000000000000000000000000000000000" {
+		return false, autherr.New(autherr.CodeAttesterMismatch, "Proof Error: attester is zero address")
+	}
+
+	signer, err := RecoverOffChainAttestationSigner(now, recipient, expectTypedData, sig)
+	if err != nil {
+		return false, err
+	}
+	return signer == attester, nil
+}
+
+// RecoverOffChainAttestationSigner checks everything of the attestation but the attester, and returns the address which signed it,
+// so that the caller can match it against several trusted attesters
+func RecoverOffChainAttestationSigner(now time.Time, recipient string, expectTypedData *apitypes.TypedData, sig *Sig) (string, error) {
+	if sig == nil || sig.TypedData == nil || sig.Signature == nil {
+		return "", autherr.New(autherr.CodeMalformed, "Proof Error: proof has no sig")
+	}
+
+	// the version decides the types and the UID
+	version, err := VersionOf(sig.TypedData)
+	if err != nil {
+		return "", autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid version", err)
+	}
+
 	// verify OffChainUID
-	offChainUID := getOffChainUID(sig.Message)
+	offChainUID := getOffChainUID(version, sig.Message)
 	if offChainUID != sig.UID {
-		return false, errors.New("Proof Error: proof uid not match")
+		return "", autherr.New(autherr.CodeMalformed, "Proof Error: proof uid not match")
 	}
 
 	// verify expiration time
 	expirationTime, err := strconv.ParseInt(fmt.Sprintf("%s", sig.Message["expirationTime"]), 10, 64)
-	if err != nil || time.Now().UTC().Unix() > expirationTime {
-		return false, errors.New("Proof Error: proof expired")
+	if err != nil {
+		return "", autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid expiration time", err)
+	}
+	if now.UTC().Unix() > expirationTime {
+		return "", autherr.New(autherr.CodeProofExpired, "Proof Error: proof expired")
 	}
 
 	// verify recipient
 	if recipient != sig.Message["recipient"] {
-		return false, errors.New("Proof Error: proof recipient not match")
+		return "", autherr.New(autherr.CodeRecipientMismatch, "Proof Error: proof recipient not match")
 	}
 
 	if !reflect.DeepEqual(sig.Domain, expectTypedData.Domain) {
-		return false, errors.New("Proof Error: domain not match")
+		return "", autherr.New(autherr.CodeMalformed, "Proof Error: domain not match")
 	}
 	if sig.PrimaryType != expectTypedData.PrimaryType {
-		return false, errors.New("Proof Error: primary type not match")
+		return "", autherr.New(autherr.CodeMalformed, "Proof Error: primary type not match")
 	}
 	// TODO Node has no `EIP712Domain` but Go has, so we can't compare `types`
 	//if !reflect.DeepEqual(sig.Types, expectTypedData.Types) {
-	//	return false, errors.New("Proof Error: types not match")
+	//	return "", errors.New("Proof Error: types not match")
 	//}
-	if attester == "0x0000000000000000000000000000000000000000" {
-		return false, errors.New("Proof Error: attester is zero address")
-	}
 
 	// <---------------------------
 	// `EIP712Domain` is empty when proof generate by Node SDK
+	if sig.TypedData.Types == nil {
+		sig.TypedData.Types = apitypes.Types{}
+	}
 	if sig.TypedData.Types["EIP712Domain"] == nil {
-		sig.TypedData.Types["EIP712Domain"] = []apitypes.Type{
-			{Name: "name", Type: "string"},
-			{Name: "version", Type: "string"},
-			{Name: "chainId", Type: "uint256"},
-			{Name: "verifyingContract", Type: "address"},
-		}
+		sig.TypedData.Types["EIP712Domain"] = append([]apitypes.Type(nil), eip712DomainType...)
 	}
-	// `Attest` 's `nonce` is empty when proof generate by Node SDK
-	hasNonce := false
-	for _, t := range sig.TypedData.Types["Attest"] {
-		if t.Name == "nonce" {
-			hasNonce = true
-			break
+	if version == Version1 {
+		// `Attest` 's `nonce` is empty when proof generate by Node SDK
+		hasNonce := false
+		for _, t := range sig.TypedData.Types["Attest"] {
+			if t.Name == "nonce" {
+				hasNonce = true
+				break
+			}
 		}
-	}
-	if !hasNonce {
-		sig.TypedData.Types["Attest"] = append(sig.TypedData.Types["Attest"], apitypes.Type{Name: "nonce", Type: "string"})
+		if !hasNonce {
+			sig.TypedData.Types["Attest"] = append(sig.TypedData.Types["Attest"], apitypes.Type{Name: "nonce", Type: "string"})
+		}
+	} else {
+		// the other versions have no `nonce`, their types are decided by the version
+		sig.TypedData.Types[version.PrimaryType()] = version.Types()[version.PrimaryType()]
 	}
 	// --------------------------->
 	// 1 signHash
 	hash, err := signHash(sig.TypedData)
 	if err != nil {
-		return false, err
+		return "", autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid typed data", err)
 	}
 	//fmt.Printf("verify-hash: %v\n", hash)
 	//fmt.Printf("verify-hash: %s\n", hexutil.Encode(hash))
 
 	// 2 signature
-	sign := convertFromRSV(sig.Signature.R, sig.Signature.S, sig.Signature.V)
+	sign, err := convertFromRSV(sig.Signature.R, sig.Signature.S, sig.Signature.V)
+	if err != nil {
+		return "", autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid signature", err)
+	}
 	//fmt.Printf("verify-signature: %v\n", sign)
 	//fmt.Printf("verify-signature: %s\n", hexutil.Encode(sign))
 
 	pubKey, err := crypto.SigToPub(hash, sign)
 	if err != nil {
-		return false, fmt.Errorf("verify signatrue error: %s", err)
+		return "", autherr.Wrap(autherr.CodeMalformed, "verify signatrue error", err)
 	}
-	return crypto.PubkeyToAddress(*pubKey).Hex() == attester, nil
+	return crypto.PubkeyToAddress(*pubKey).Hex(), nil
 }
 
 func signHash(typedData *apitypes.TypedData) ([]byte, error) {
@@ -157,28 +212,36 @@ func convertToRSV(signature []byte) (r, s string, v uint8) {
 	return hexutil.EncodeBig(rb), hexutil.EncodeBig(sb), v
 }
 
-func convertFromRSV(r, s string, v uint8) (signature []byte) {
+func convertFromRSV(r, s string, v uint8) (signature []byte, err error) {
 	signature = make([]byte, 65)
 
 	// r
 	bigR, err := hexutil.DecodeBig(r)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	copy(signature[:32], bigR.Bytes()) // 0~31
+	if bigR.BitLen() > 256 {
+		return nil, errors.New("r is longer than 32 bytes")
+	}
+	bigR.FillBytes(signature[:32]) // 0~31
 	// s
 	bigS, err := hexutil.DecodeBig(s)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	if bigS.BitLen() > 256 {
+		return nil, errors.New("s is longer than 32 bytes")
 	}
-	copy(signature[32:64], bigS.Bytes()) // 32~63
+	bigS.FillBytes(signature[32:64]) // 32~63
 	// v
 	signature[64] = v - 27
 
 	return
 }
 
-func getOffChainUID(typedDataMessage apitypes.TypedDataMessage) string {
+// getOffChainUID returns the UID of an attestation of `version`, it's `solidityPackedKeccak256` of the message like the EAS SDK,
+// the version 0 has no `version`, and the version 2 has the `salt` after `data`
+func getOffChainUID(version Version, typedDataMessage apitypes.TypedDataMessage) string {
 	// uint16 `solidityPackedKeccak256(["uint16"], [1])` ==> `0x49d03a195e239b52779866b33024210fc7dc66e9c2998975c0aa45c1702549d5`
 	//i := uint16(1)
 	//b := make([]byte, 2)
@@ -214,21 +277,22 @@ func getOffChainUID(typedDataMessage apitypes.TypedDataMessage) string {
 	//slice, _ := hexutil.Decode("0x0000000000000000000000000000000000000000000000000000000000000000")
 	//hash := crypto.Keccak256Hash(slice) // ok!
 
-	v, _ := strconv.ParseUint(fmt.Sprintf("%s", typedDataMessage["version"]), 10, 16)
-	tim, _ := strconv.ParseUint(fmt.Sprintf("%s", typedDataMessage["time"]), 10, 64)
-	expirationTime, _ := strconv.ParseUint(fmt.Sprintf("%s", typedDataMessage["expirationTime"]), 10, 64)
+	tim, _ := strconv.ParseUint(messageString(typedDataMessage, "time"), 10, 64)
+	expirationTime, _ := strconv.ParseUint(messageString(typedDataMessage, "expirationTime"), 10, 64)
+	revocable, _ := typedDataMessage["revocable"].(bool)
 	var (
-		version   uint16 = uint16(v)
 		schema    string = fmt.Sprintf("%s", typedDataMessage["schema"])
 		recipient string = fmt.Sprintf("%s", typedDataMessage["recipient"])
-		revocable bool   = typedDataMessage["revocable"].(bool)
 		refUID    string = fmt.Sprintf("%s", typedDataMessage["refUID"])
 		data      string = fmt.Sprintf("%s", typedDataMessage["data"])
 	)
 
-	// `["uint16", "bytes", "address", "address", "uint64", "uint64", "bool", "bytes32", "bytes", "uint32"]`
-	hash := crypto.Keccak256Hash(
-		uin16Bytes(version),
+	// `["uint16", "bytes", "address", "address", "uint64", "uint64", "bool", "bytes32", "bytes", "bytes32", "uint32"]`
+	var packed [][]byte
+	if version != Version0 {
+		packed = append(packed, uin16Bytes(uint16(version)))
+	}
+	packed = append(packed,
 		bytesBytes(schema),
 		addressBytes(recipient),
 		addressBytes("0x0000000000000000000000000000000000000000"),
@@ -237,10 +301,13 @@ func getOffChainUID(typedDataMessage apitypes.TypedDataMessage) string {
 		boolBytes(revocable),
 		bytes32Bytes(refUID),
 		bytes32Bytes(data), // NOTICE HERE, not `bytesBytes(data)`
-		uint32Bytes(0),
 	)
+	if version == Version2 {
+		packed = append(packed, bytes32Bytes(fmt.Sprintf("%s", typedDataMessage["salt"])))
+	}
+	packed = append(packed, uint32Bytes(0))
 
-	return hash.Hex()
+	return crypto.Keccak256Hash(packed...).Hex()
 }
 
 func uin16Bytes(i uint16) []byte {
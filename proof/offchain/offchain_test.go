@@ -1,6 +1,7 @@
 package offchain
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ var (
 	privateKey, _ = crypto.HexToECDSA("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
 	attester      = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
 	recipient     = "0x0000000000000000000000000000000000000000"
+	schemaUID     = "0x32275eb98dcb8f82848adef9fa52311cc9e83bc6fdb34c5f46ac4b8d957ad3d9"
 
 	primaryType = "Attest"
 	types       = apitypes.Types{
@@ -79,7 +81,7 @@ func TestSignOffChainAttestation(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := SignOffChainAttestation(privateKey, tt.args.typedData)
+			got, err := SignOffChainAttestation(context.Background(), NewKeySigner(privateKey), tt.args.typedData)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SignOffChainAttestation() error = %v, wantErr = %v", err, tt.wantErr)
 				return
@@ -100,6 +102,7 @@ func TestVerifyOffChainAttestation(t *testing.T) {
 		recipient       string
 		typedData       *apitypes.TypedData
 		expectTypedData *apitypes.TypedData
+		now             time.Time // zero means current time
 	}
 	tests := []struct {
 		name    string
@@ -183,6 +186,7 @@ func TestVerifyOffChainAttestation(t *testing.T) {
 					PrimaryType: primaryType,
 					Domain:      typedDataDomain,
 				},
+				now: time.Unix(1704249694, 0),
 			},
 			want:    true,
 			wantErr: false,
@@ -190,7 +194,7 @@ func TestVerifyOffChainAttestation(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sig, err := SignOffChainAttestation(privateKey, tt.args.typedData)
+			sig, err := SignOffChainAttestation(context.Background(), NewKeySigner(privateKey), tt.args.typedData)
 			if err != nil {
 				t.Errorf("SignOffChainAttestation() error = %v", err)
 				return
@@ -198,7 +202,11 @@ func TestVerifyOffChainAttestation(t *testing.T) {
 			//t.Logf("Proof Message = %v", tt.args.typedData.Message)
 			//t.Logf("Proof Signature: %+v", sig.Signature)
 
-			got, err := VerifyOffChainAttestation(tt.args.attester, tt.args.recipient, tt.args.expectTypedData, sig)
+			now := tt.args.now
+			if now.IsZero() {
+				now = time.Now()
+			}
+			got, err := VerifyOffChainAttestationAt(now, tt.args.attester, tt.args.recipient, tt.args.expectTypedData, sig)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("VerifyOffChainAttestation() error = %v, wantErr = %v", err, tt.wantErr)
 				return
@@ -212,12 +220,14 @@ func TestVerifyOffChainAttestation(t *testing.T) {
 
 func Test_getOffChainUID(t *testing.T) {
 	tests := []struct {
-		name string
-		args apitypes.TypedDataMessage
-		want string
+		name    string
+		version Version
+		args    apitypes.TypedDataMessage
+		want    string
 	}{
 		{
-			name: "ok",
+			name:    "ok",
+			version: Version1,
 			args: apitypes.TypedDataMessage{
 				"version":        "1",
 				"schema":         "0x32275eb98dcb8f82848adef9fa52311cc9e83bc6fdb34c5f46ac4b8d957ad3d9",
@@ -230,12 +240,94 @@ func Test_getOffChainUID(t *testing.T) {
 			},
 			want: "0x27600687657c97bcdd6d137c62e727c805ac563b94fdc08b1ffe9d15cbd6f55d",
 		},
+		{
+			name:    "version 1 by Node SDK",
+			version: Version1,
+			args: apitypes.TypedDataMessage{
+				"version":        float64(1),
+				"schema":         "0x32275eb98dcb8f82848adef9fa52311cc9e83bc6fdb34c5f46ac4b8d957ad3d9",
+				"recipient":      "0x0000000000000000000000000000000000000000",
+				"time":           "1703962538",
+				"expirationTime": "1703962537",
+				"revocable":      true,
+				"refUID":         "0x0000000000000000000000000000000000000000000000000000000000000000",
+				"data":           "0x000000000000000000000000f39fd6e51aad88f6f4ce6ab8827279cfffb92266000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000036f732b0000000000000000000000000000000000000000000000000000000000",
+			},
+			want: "0x27600687657c97bcdd6d137c62e727c805ac563b94fdc08b1ffe9d15cbd6f55d",
+		},
+		{
+			name:    "version 0",
+			version: Version0,
+			args: apitypes.TypedDataMessage{
+				"schema":         "0x32275eb98dcb8f82848adef9fa52311cc9e83bc6fdb34c5f46ac4b8d957ad3d9",
+				"recipient":      "0x0000000000000000000000000000000000000000",
+				"time":           "1703962538",
+				"expirationTime": "1703962537",
+				"revocable":      true,
+				"refUID":         "0x0000000000000000000000000000000000000000000000000000000000000000",
+				"data":           "0x000000000000000000000000f39fd6e51aad88f6f4ce6ab8827279cfffb92266000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000036f732b0000000000000000000000000000000000000000000000000000000000",
+			},
+			want: "0xb9af97386b265fd72da0140ce1b5a15d2dcff533f6e0c12da60b79e1490c7e84",
+		},
+		{
+			name:    "version 2",
+			version: Version2,
+			args: apitypes.TypedDataMessage{
+				"version":        "2",
+				"salt":           "0x0101010101010101010101010101010101010101010101010101010101010101",
+				"schema":         "0x32275eb98dcb8f82848adef9fa52311cc9e83bc6fdb34c5f46ac4b8d957ad3d9",
+				"recipient":      "0x0000000000000000000000000000000000000000",
+				"time":           "1703962538",
+				"expirationTime": "1703962537",
+				"revocable":      true,
+				"refUID":         "0x0000000000000000000000000000000000000000000000000000000000000000",
+				"data":           "0x000000000000000000000000f39fd6e51aad88f6f4ce6ab8827279cfffb92266000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000036f732b0000000000000000000000000000000000000000000000000000000000",
+			},
+			want: "0x5fbf1ee8eba0887bf0fb40ca78d534a930f99f0a70c2475c559c1bb142a8e61f",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := getOffChainUID(tt.args); got != tt.want {
+			if got := getOffChainUID(tt.version, tt.args); got != tt.want {
 				t.Errorf("getOffChainUID() = %v, want = %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_convertFromRSV(t *testing.T) {
+	type args struct {
+		r string
+		s string
+		v uint8
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "ok",
+			args:    args{r: "0xe5fb48f59cb1bb9cfca82fc29c2fe91d1d8e3650dcfafa7ca62ee6e46250c2ec", s: "0x4ec2f2c8e166be709bce43c23cb8534e68003d39dd0610056d93311738f63577", v: 28},
+			wantErr: false,
+		},
+		{
+			name:    "invalid hex",
+			args:    args{r: "0xzz", s: "0x4ec2f2c8e166be709bce43c23cb8534e68003d39dd0610056d93311738f63577", v: 28},
+			wantErr: true,
+		},
+		{
+			name:    "too long",
+			args:    args{r: "0x1e5fb48f59cb1bb9cfca82fc29c2fe91d1d8e3650dcfafa7ca62ee6e46250c2ec", s: "0x4ec2f2c8e166be709bce43c23cb8534e68003d39dd0610056d93311738f63577", v: 28},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := convertFromRSV(tt.args.r, tt.args.s, tt.args.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("convertFromRSV() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
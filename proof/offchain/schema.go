@@ -0,0 +1,114 @@
+package offchain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SchemaField is a named and typed field of an EAS schema
+type SchemaField struct {
+	Name       string
+	Type       string        // the solidity type, e.g. `address` or `string[]`, it's `tuple`, `tuple[]` or `tuple[N]` for a tuple
+	Components []SchemaField // the fields of a tuple
+}
+
+// ABIType returns the ABI type of the field
+func (f *SchemaField) ABIType() (abi.Type, error) {
+	return abi.NewType(f.Type, "", f.argumentMarshaling().Components)
+}
+
+func (f *SchemaField) argumentMarshaling() abi.ArgumentMarshaling {
+	m := abi.ArgumentMarshaling{Name: f.Name, Type: f.Type}
+	for i := range f.Components {
+		m.Components = append(m.Components, f.Components[i].argumentMarshaling())
+	}
+	return m
+}
+
+// ParseSchema parses an EAS schema string, e.g. `string signature,address wallet,string vendor`.
+// A tuple is written as `(uint256 id,int8 level)[] badges`
+func ParseSchema(schema string) ([]SchemaField, error) {
+	fields, err := parseSchemaFields(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %q: %w", schema, err)
+	}
+	for i := range fields {
+		if _, err = fields[i].ABIType(); err != nil {
+			return nil, fmt.Errorf("invalid schema %q: field %s: %w", schema, fields[i].Name, err)
+		}
+	}
+	return fields, nil
+}
+
+func parseSchemaFields(schema string) ([]SchemaField, error) {
+	var fields []SchemaField
+	names := make(map[string]bool)
+	for _, f := range splitSchema(schema) {
+		f = strings.TrimSpace(f)
+		var field SchemaField
+		if strings.HasPrefix(f, "(") {
+			end := strings.LastIndexByte(f, ')')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed tuple %q", f)
+			}
+			components, err := parseSchemaFields(f[1:end])
+			if err != nil {
+				return nil, err
+			}
+			rest := strings.Fields(f[end+1:])
+			if len(rest) != 2 && !(len(rest) == 1 && !strings.HasPrefix(rest[0], "[")) {
+				return nil, fmt.Errorf("invalid field %q", f)
+			}
+			field = SchemaField{Type: "tuple", Name: rest[len(rest)-1], Components: components}
+			if len(rest) == 2 {
+				field.Type += rest[0]
+			}
+		} else {
+			parts := strings.Fields(f)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid field %q, expect `<type> <name>`", f)
+			}
+			field = SchemaField{Type: parts[0], Name: parts[1]}
+		}
+		if names[field.Name] {
+			return nil, fmt.Errorf("duplicate field %s", field.Name)
+		}
+		names[field.Name] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// splitSchema splits the schema by the commas which are not in a tuple
+func splitSchema(schema string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range schema {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, schema[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, schema[start:])
+}
+
+// ComputeSchemaUID computes the UID of a schema registered on the EAS SchemaRegistry,
+// which is `keccak256(abi.encodePacked(schema, resolver, revocable))`
+func ComputeSchemaUID(schema, resolver string, revocable bool) string {
+	revocableByte := byte(0)
+	if revocable {
+		revocableByte = 1
+	}
+	return crypto.Keccak256Hash([]byte(schema), common.HexToAddress(resolver).Bytes(), []byte{revocableByte}).Hex()
+}
@@ -0,0 +1,124 @@
+package offchain
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		want    []SchemaField
+		wantErr bool
+	}{
+		{
+			name:   "SeeAuth",
+			schema: "string signature,address wallet,string vendor",
+			want:   []SchemaField{{Name: "signature", Type: "string"}, {Name: "wallet", Type: "address"}, {Name: "vendor", Type: "string"}},
+		},
+		{
+			name:   "arrays and spaces",
+			schema: " string[] roles , uint8 tier,bytes32[2] refs",
+			want:   []SchemaField{{Name: "roles", Type: "string[]"}, {Name: "tier", Type: "uint8"}, {Name: "refs", Type: "bytes32[2]"}},
+		},
+		{
+			name:   "tuples",
+			schema: "(uint256 id,int8 level) badge,(uint256 id,(string name) meta)[] badges",
+			want: []SchemaField{
+				{Name: "badge", Type: "tuple", Components: []SchemaField{{Name: "id", Type: "uint256"}, {Name: "level", Type: "int8"}}},
+				{Name: "badges", Type: "tuple[]", Components: []SchemaField{
+					{Name: "id", Type: "uint256"},
+					{Name: "meta", Type: "tuple", Components: []SchemaField{{Name: "name", Type: "string"}}},
+				}},
+			},
+		},
+		{name: "no name", schema: "string signature,address", wantErr: true},
+		{name: "invalid type", schema: "strin signature", wantErr: true},
+		{name: "uint without size", schema: "uint tier", wantErr: true},
+		{name: "duplicate name", schema: "string wallet,address wallet", wantErr: true},
+		{name: "unclosed tuple", schema: "(uint256 id badge", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSchema(tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSchema() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSchema() got = %+v, want = %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeSchemaUID(t *testing.T) {
+	tests := []struct {
+		name      string
+		schema    string
+		resolver  string
+		revocable bool
+		want      string
+	}{
+		{
+			name:      "SeeAuth",
+			schema:    "string signature,address wallet,string vendor",
+			resolver:  "0x0000000000000000000000000000000000000000",
+			revocable: true,
+			want:      "0x57da98d8f7e4e1f47ac9d0de2f2d408dc93d0639c2d713903b47b036c3fd10f7",
+		},
+		{
+			name:      "SeeAuth non-EVM",
+			schema:    "string signature,string wallet,string vendor",
+			resolver:  "0x0000000000000000000000000000000000000000",
+			revocable: true,
+			want:      "0xb375d491164124f9e98e6a06f3975ea4245df95a9e04514e9269e3c9597025a2",
+		},
+		{
+			name:      "not revocable",
+			schema:    "string signature,address wallet,string vendor",
+			resolver:  "0x0000000000000000000000000000000000000000",
+			revocable: false,
+			want:      "0x702f8e88e4011431def008cbc53839ee6443d30883888d1f5af62fb27fc0e491",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeSchemaUID(tt.schema, tt.resolver, tt.revocable); got != tt.want {
+				t.Errorf("ComputeSchemaUID() got = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeFields(t *testing.T) {
+	fields, err := ParseSchema("(uint256 id,int8 level)[] badges,uint8 tier")
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	badges := []badge{{ID: nil, Level: 1}}
+	if _, err = EncodeFields(fields, []any{badges, 1}); err == nil {
+		t.Errorf("EncodeFields() of nil *big.Int error = nil")
+	}
+	if _, err = EncodeFields(fields, []any{badges[:0], nil}); err == nil || !strings.Contains(err.Error(), "field tier: nil value") {
+		t.Errorf("EncodeFields() of nil error = %v, want nil value", err)
+	}
+
+	data, err := EncodeFields(fields, []any{[]badge{{ID: big.NewInt(1), Level: -1}}, 2})
+	if err != nil {
+		t.Fatalf("EncodeFields() error = %v", err)
+	}
+	var gotBadges []badge
+	var gotTier int
+	if err = DecodeFields(fields, data, []any{&gotBadges, &gotTier}); err != nil {
+		t.Fatalf("DecodeFields() error = %v", err)
+	}
+	if len(gotBadges) != 1 || gotBadges[0].ID.Cmp(big.NewInt(1)) != 0 || gotBadges[0].Level != -1 || gotTier != 2 {
+		t.Errorf("DecodeFields() got = %+v, %v", gotBadges, gotTier)
+	}
+	if err = DecodeFields(fields, data, []any{gotBadges, &gotTier}); err == nil {
+		t.Errorf("DecodeFields() to non-pointer error = nil")
+	}
+}
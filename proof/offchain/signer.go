@@ -0,0 +1,145 @@
+package offchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer signs attestations, the key can be kept in memory, in a keystore or by a remote signer
+type Signer interface {
+	// Address returns the address of the attester
+	Address() common.Address
+	// SignHash signs the 32 bytes `hash`, the signature is `r || s || v` with `v` of 0 or 1
+	SignHash(ctx context.Context, hash []byte) ([]byte, error)
+}
+
+// TypedDataSigner is implemented by signers which sign the EIP-712 typed data instead of the hash, e.g. Clef.
+// `SignOffChainAttestation` prefers `SignTypedData` when the signer implements it
+type TypedDataSigner interface {
+	Signer
+	SignTypedData(ctx context.Context, typedData *apitypes.TypedData) ([]byte, error)
+}
+
+// KeySigner signs by an ECDSA private key in memory
+type KeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKeySigner creates a KeySigner of `key`
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key}
+}
+
+// NewHexKeySigner creates a KeySigner of the hex private key, with or without `0x`
+func NewHexKeySigner(hexKey string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(key), nil
+}
+
+// NewEnvSigner creates a KeySigner of the hex private key in the environment variable `name`
+func NewEnvSigner(name string) (*KeySigner, error) {
+	hexKey, ok := os.LookupEnv(name)
+	if !ok || hexKey == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", name)
+	}
+	signer, err := NewHexKeySigner(strings.TrimSpace(hexKey))
+	if err != nil {
+		return nil, fmt.Errorf("environment variable %s: %w", name, err)
+	}
+	return signer, nil
+}
+
+// NewKeystoreSigner creates a KeySigner of the go-ethereum encrypted keystore JSON, which is decrypted by `passphrase`
+func NewKeystoreSigner(keyJSON []byte, passphrase string) (*KeySigner, error) {
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt keystore: %w", err)
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// NewKeystoreFileSigner is like `NewKeystoreSigner`, but the keystore JSON is read from the file at `path`
+func NewKeystoreFileSigner(path, passphrase string) (*KeySigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeystoreSigner(keyJSON, passphrase)
+}
+
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *KeySigner) SignHash(_ context.Context, hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+// DefaultClefTimeout is used when `ClefSigner.Timeout` is zero, Clef may wait for a manual approval
+const DefaultClefTimeout = 30 * time.Second
+
+// ErrHashSigningUnsupported is returned by `ClefSigner.SignHash`, Clef never signs a raw hash
+var ErrHashSigningUnsupported = errors.New("signer can't sign a raw hash")
+
+// ClefSigner signs by a Clef-compatible remote signer with `account_signTypedData`, the key never leaves the signer
+type ClefSigner struct {
+	URL     string         // e.g. `http://localhost:8550`, or the path of the IPC socket
+	Account common.Address // the account in the signer
+	Headers http.Header
+	Timeout time.Duration // timeout of every call, `DefaultClefTimeout` if zero
+}
+
+func (s *ClefSigner) Address() common.Address {
+	return s.Account
+}
+
+// SignHash always fails, Clef only signs typed data, see `SignTypedData`
+func (s *ClefSigner) SignHash(context.Context, []byte) ([]byte, error) {
+	return nil, ErrHashSigningUnsupported
+}
+
+// SignTypedData signs `typedData` by `account_signTypedData`
+func (s *ClefSigner) SignTypedData(ctx context.Context, typedData *apitypes.TypedData) ([]byte, error) {
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = DefaultClefTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := rpc.DialOptions(ctx, s.URL, rpc.WithHeaders(s.Headers))
+	if err != nil {
+		return nil, fmt.Errorf("dial signer: %w", err)
+	}
+	defer client.Close()
+
+	var sig hexutil.Bytes
+	err = client.CallContext(ctx, &sig, "account_signTypedData", s.Account, typedData)
+	if err != nil {
+		return nil, fmt.Errorf("account_signTypedData: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("account_signTypedData: invalid signature length %d", len(sig))
+	}
+	// Clef returns `v` of 27 or 28
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	return sig, nil
+}
@@ -0,0 +1,141 @@
+package offchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/google/uuid"
+)
+
+// clefService is a stub of Clef, it signs typed data by `key` for any account
+type clefService struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *clefService) SignTypedData(_ context.Context, _ common.Address, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+func newClefServer(t *testing.T, key *ecdsa.PrivateKey) string {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &clefService{key: key}); err != nil {
+		t.Fatalf("RegisterName() error = %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func newKeystoreJSON(t *testing.T, key *ecdsa.PrivateKey, passphrase string) []byte {
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("EncryptKey() error = %v", err)
+	}
+	return keyJSON
+}
+
+func TestSigner(t *testing.T) {
+	otherKey, _ := crypto.GenerateKey()
+	keyJSON := newKeystoreJSON(t, privateKey, "passphrase")
+	t.Setenv("SEEAUTH_TEST_ATTESTER_KEY", "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+
+	tests := []struct {
+		name      string
+		newSigner func() (Signer, error)
+		wantErr   bool
+	}{
+		{
+			name:      "key",
+			newSigner: func() (Signer, error) { return NewKeySigner(privateKey), nil },
+			wantErr:   false,
+		},
+		{
+			name:      "env",
+			newSigner: func() (Signer, error) { return NewEnvSigner("SEEAUTH_TEST_ATTESTER_KEY") },
+			wantErr:   false,
+		},
+		{
+			name:      "env not set",
+			newSigner: func() (Signer, error) { return NewEnvSigner("SEEAUTH_TEST_NOT_SET") },
+			wantErr:   true,
+		},
+		{
+			name:      "keystore",
+			newSigner: func() (Signer, error) { return NewKeystoreSigner(keyJSON, "passphrase") },
+			wantErr:   false,
+		},
+		{
+			name:      "keystore wrong passphrase",
+			newSigner: func() (Signer, error) { return NewKeystoreSigner(keyJSON, "wrong") },
+			wantErr:   true,
+		},
+		{
+			name: "clef",
+			newSigner: func() (Signer, error) {
+				return &ClefSigner{URL: newClefServer(t, privateKey), Account: common.HexToAddress(attester)}, nil
+			},
+			wantErr: false,
+		},
+		{
+			name: "clef signs by another account",
+			newSigner: func() (Signer, error) {
+				return &ClefSigner{URL: newClefServer(t, otherKey), Account: common.HexToAddress(attester)}, nil
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typedData := &apitypes.TypedData{
+				Types:       types,
+				PrimaryType: primaryType,
+				Domain:      typedDataDomain,
+				Message:     typedDataMessage,
+			}
+			signer, err := tt.newSigner()
+			if err == nil {
+				_, err = SignOffChainAttestation(context.Background(), signer, typedData)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SignOffChainAttestation() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && signer.Address().Hex() != attester {
+				t.Errorf("Address() = %v, want = %v", signer.Address().Hex(), attester)
+			}
+		})
+	}
+}
+
+func TestClefSigner_SignHash(t *testing.T) {
+	signer := &ClefSigner{URL: "http://127.0.0.1:0", Account: common.HexToAddress(attester)}
+	_, err := signer.SignHash(context.Background(), make([]byte, 32))
+	if !errors.Is(err, ErrHashSigningUnsupported) {
+		t.Errorf("SignHash() error = %v, want = %v", err, ErrHashSigningUnsupported)
+	}
+}
@@ -0,0 +1,221 @@
+package offchain
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// URLPrefix is the path of shareable off-chain attestations on EASScan, e.g. `https://base.easscan.org/offchain/url/#attestation=…`
+const URLPrefix = "/offchain/url/#attestation="
+
+// maxURLAttestationSize is the max size of an inflated attestation of `DecodeURL`, the data of a schema is rarely larger than some KB
+const maxURLAttestationSize = 1 << 20
+
+// EncodeURL encodes the attestation `sig` signed by `signer` as a shareable URL of EASScan, without the host.
+// The attestation is compacted into a JSON array like the EAS SDK, deflated, and encoded by base64.
+func EncodeURL(sig *Sig, signer string) (string, error) {
+	if sig == nil || sig.TypedData == nil || sig.Signature == nil {
+		return "", errors.New("attestation has no sig")
+	}
+	version, err := VersionOf(sig.TypedData)
+	if err != nil {
+		return "", err
+	}
+	if sig.Domain.ChainId == nil {
+		return "", errors.New("attestation has no chain ID")
+	}
+	tim, err := strconv.ParseUint(messageString(sig.Message, "time"), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid time: %w", err)
+	}
+	expirationTime, err := strconv.ParseUint(messageString(sig.Message, "expirationTime"), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid expiration time: %w", err)
+	}
+	recipient := messageString(sig.Message, "recipient")
+	if common.HexToAddress(recipient) == (common.Address{}) {
+		recipient = "0"
+	}
+	refUID := messageString(sig.Message, "refUID")
+	if refUID == zeroBytes32 {
+		refUID = "0"
+	}
+	revocable, _ := sig.Message["revocable"].(bool)
+
+	// `[domain.version, chainId, verifyingContract, r, s, v, signer, uid, schema, recipient, time, expirationTime, refUID, revocable, data, nonce, version, salt]`
+	compacted := []any{
+		sig.Domain.Version,
+		(*big.Int)(sig.Domain.ChainId).String(), // a string like the bigint of the EAS SDK
+		sig.Domain.VerifyingContract,
+		sig.Signature.R,
+		sig.Signature.S,
+		sig.Signature.V,
+		signer,
+		sig.UID,
+		messageString(sig.Message, "schema"),
+		recipient,
+		tim,
+		expirationTime,
+		refUID,
+		revocable,
+		messageString(sig.Message, "data"),
+		0,
+		nil,
+		nil,
+	}
+	if version != Version0 {
+		compacted[16] = uint16(version)
+	}
+	if version == Version2 {
+		compacted[17] = messageString(sig.Message, "salt")
+	}
+	b, err := json.Marshal(compacted)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	w, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err = w.Write(b); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	return URLPrefix + url.QueryEscape(base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// DecodeURL decodes a shareable URL of EASScan into the attestation and its signer, see `EncodeURL`.
+// `u` can be a full URL, a URL without the host, or only the encoded attestation.
+func DecodeURL(u string) (*Sig, string, error) {
+	encoded := u
+	if i := strings.Index(u, "attestation="); i >= 0 {
+		encoded = u[i+len("attestation="):]
+	}
+	encoded, err := url.PathUnescape(encoded) // not `QueryUnescape`, a pasted `+` of base64 isn't a space
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid url: %w", err)
+	}
+	// the EAS SDK encodes by the standard base64, but the URL-safe base64 is accepted too
+	encoded = strings.TrimRight(strings.NewReplacer("-", "+", "_", "/").Replace(encoded), "=")
+	deflated, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid base64: %w", err)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(deflated))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid deflated data: %w", err)
+	}
+	defer zr.Close()
+	// the URL is untrusted, a small one may be inflated into GBs
+	b, err := io.ReadAll(io.LimitReader(zr, maxURLAttestationSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid deflated data: %w", err)
+	}
+	if len(b) > maxURLAttestationSize {
+		return nil, "", fmt.Errorf("invalid attestation: larger than %d bytes", maxURLAttestationSize)
+	}
+
+	var compacted []json.RawMessage
+	if err = json.Unmarshal(b, &compacted); err != nil {
+		return nil, "", fmt.Errorf("invalid attestation: %w", err)
+	}
+	if len(compacted) < 15 {
+		return nil, "", fmt.Errorf("invalid attestation: %d fields, want at least 15", len(compacted))
+	}
+	for len(compacted) < 18 { // `nonce`, `version` and `salt` are missing in the legacy version
+		compacted = append(compacted, json.RawMessage("null"))
+	}
+
+	var (
+		domainVersion, verifyingContract, sigR, sigS, signer, uid, schema, recipient, refUID, data, salt string
+		sigV                                                                                             uint8
+		revocable                                                                                        bool
+		tim, expirationTime, version                                                                     uint64
+		chainID                                                                                          *math.HexOrDecimal256
+	)
+	for i, dst := range []any{
+		&domainVersion, &chainID, &verifyingContract, &sigR, &sigS, &sigV, &signer, &uid, &schema, &recipient,
+		&tim, &expirationTime, &refUID, &revocable, &data, nil, &version, &salt,
+	} {
+		if dst == nil {
+			continue
+		}
+		if err = unmarshalCompacted(compacted[i], dst); err != nil {
+			return nil, "", fmt.Errorf("invalid attestation field %d: %w", i, err)
+		}
+	}
+	if chainID == nil {
+		return nil, "", errors.New("invalid attestation: no chain ID")
+	}
+	if recipient == "0" {
+		recipient = common.Address{}.Hex()
+	}
+	if refUID == "0" {
+		refUID = zeroBytes32
+	}
+	if version > uint64(Version2) {
+		return nil, "", fmt.Errorf("unsupported version %d", version)
+	}
+	if version == uint64(Version2) && salt == "" {
+		return nil, "", errors.New("invalid attestation: no salt")
+	}
+
+	domain := apitypes.TypedDataDomain{
+		Name:              "EAS Attestation",
+		Version:           domainVersion,
+		ChainId:           chainID,
+		VerifyingContract: verifyingContract,
+	}
+	typedData, err := NewTypedData(Version(version), domain, &Attestation{
+		Schema:         schema,
+		Recipient:      recipient,
+		Time:           tim,
+		ExpirationTime: expirationTime,
+		Revocable:      revocable,
+		RefUID:         refUID,
+		Data:           data,
+		Salt:           salt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return &Sig{
+		TypedData: typedData,
+		Signature: &signature{R: sigR, S: sigS, V: sigV},
+		UID:       uid,
+	}, signer, nil
+}
+
+// unmarshalCompacted unmarshals a field of a compacted attestation, numbers may be strings since they are bigint in the EAS SDK
+func unmarshalCompacted(raw json.RawMessage, dst any) error {
+	if string(raw) == "null" {
+		return nil
+	}
+	if n, ok := dst.(*uint64); ok && len(raw) > 0 && raw[0] == '"' {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return err
+		}
+		var err error
+		*n, err = strconv.ParseUint(str, 10, 64)
+		return err
+	}
+	return json.Unmarshal(raw, dst)
+}
@@ -0,0 +1,139 @@
+package offchain
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+func TestEncodeURL(t *testing.T) {
+	now := time.Unix(1704249694, 0)
+	attestation := &Attestation{
+		Schema:         schemaUID,
+		Recipient:      recipient,
+		Time:           uint64(now.Unix()),
+		ExpirationTime: uint64(now.Add(time.Minute).Unix()),
+		Revocable:      true,
+		Data:           typedDataMessage["data"].(string),
+	}
+	for _, version := range []Version{Version0, Version1, Version2} {
+		typedData, err := NewTypedData(version, typedDataDomain, attestation)
+		if err != nil {
+			t.Fatalf("NewTypedData(%d) error = %v", version, err)
+		}
+		sig, err := SignOffChainAttestation(context.Background(), NewKeySigner(privateKey), typedData)
+		if err != nil {
+			t.Fatalf("SignOffChainAttestation(%d) error = %v", version, err)
+		}
+
+		u, err := EncodeURL(sig, attester)
+		if err != nil {
+			t.Fatalf("EncodeURL(%d) error = %v", version, err)
+		}
+		if !strings.HasPrefix(u, URLPrefix) {
+			t.Errorf("EncodeURL(%d) = %v, want prefix %v", version, u, URLPrefix)
+		}
+		// the chain ID is compacted as a string like the EAS SDK
+		if compacted := inflateURL(t, u); !strings.HasPrefix(compacted, `["`+typedDataDomain.Version+`","80001",`) {
+			t.Errorf("EncodeURL(%d) compacted = %v, want the chain ID as a string", version, compacted)
+		}
+
+		got, signer, err := DecodeURL("https://polygon-mumbai.easscan.org" + u)
+		if err != nil {
+			t.Fatalf("DecodeURL(%d) error = %v", version, err)
+		}
+		if signer != attester {
+			t.Errorf("DecodeURL(%d) signer = %v, want = %v", version, signer, attester)
+		}
+		if got.UID != sig.UID {
+			t.Errorf("DecodeURL(%d) uid = %v, want = %v", version, got.UID, sig.UID)
+		}
+		expectTypedData := &apitypes.TypedData{Types: version.Types(), PrimaryType: version.PrimaryType(), Domain: typedDataDomain}
+		ok, err := VerifyOffChainAttestationAt(now, attester, recipient, expectTypedData, got)
+		if err != nil || !ok {
+			t.Errorf("VerifyOffChainAttestationAt(%d) = %v, error = %v", version, ok, err)
+		}
+	}
+}
+
+func TestDecodeURL(t *testing.T) {
+	// `deflate` compresses like pako of the EAS SDK
+	deflate := func(s string) []byte {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		_, _ = w.Write([]byte(s))
+		_ = w.Close()
+		return buf.Bytes()
+	}
+	legacy := `["1.2.0","80001","0xaEF4103A04090071165F78D45D83A0C0782c2B2a","0x1","0x2",28,"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266","0x3",` +
+		`"0x32275eb98dcb8f82848adef9fa52311cc9e83bc6fdb34c5f46ac4b8d957ad3d9","0",1704249694,"1704249754","0",true,"0x"]`
+
+	tests := []struct {
+		name        string
+		u           string
+		wantVersion Version
+		wantErr     bool
+	}{
+		{name: "standard base64", u: URLPrefix + base64.StdEncoding.EncodeToString(deflate(legacy)), wantVersion: Version0},
+		{name: "URL-safe base64", u: base64.RawURLEncoding.EncodeToString(deflate(legacy)), wantVersion: Version0},
+		{name: "too few fields", u: base64.StdEncoding.EncodeToString(deflate(`["1.2.0","80001"]`)), wantErr: true},
+		{name: "unsupported version", u: base64.StdEncoding.EncodeToString(deflate(strings.TrimSuffix(legacy, "]") + `,0,3,null]`)), wantErr: true},
+		{name: "no salt", u: base64.StdEncoding.EncodeToString(deflate(strings.TrimSuffix(legacy, "]") + `,0,2,null]`)), wantErr: true},
+		{name: "too large", u: base64.StdEncoding.EncodeToString(deflate(strings.TrimSuffix(legacy, "]") + strings.Repeat(",0", maxURLAttestationSize) + "]")), wantErr: true},
+		{name: "not deflated", u: base64.StdEncoding.EncodeToString([]byte(legacy)), wantErr: true},
+		{name: "not base64", u: URLPrefix + "!!!", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := DecodeURL(tt.u)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeURL() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			version, err := VersionOf(got.TypedData)
+			if err != nil || version != tt.wantVersion {
+				t.Errorf("DecodeURL() version = %v, error = %v, want = %v", version, err, tt.wantVersion)
+			}
+			if got.Domain.ChainId == nil || (*big.Int)(got.Domain.ChainId).Int64() != 80001 {
+				t.Errorf("DecodeURL() chain ID = %v, want = 80001", got.Domain.ChainId)
+			}
+			if got.Message["recipient"] != recipient || got.Message["refUID"] != zeroBytes32 {
+				t.Errorf("DecodeURL() recipient = %v, refUID = %v", got.Message["recipient"], got.Message["refUID"])
+			}
+		})
+	}
+}
+
+// inflateURL returns the compacted attestation of the URL `u` encoded by `EncodeURL`
+func inflateURL(t *testing.T, u string) string {
+	t.Helper()
+	encoded, err := url.QueryUnescape(strings.TrimPrefix(u, URLPrefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deflated, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(deflated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
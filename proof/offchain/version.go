@@ -0,0 +1,157 @@
+package offchain
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Version is the version of EAS off-chain attestations
+type Version uint16
+
+const (
+	Version0 Version = 0 // the legacy `Attestation` type, without `version`
+	Version1 Version = 1 // the `Attest` type with `version`
+	Version2 Version = 2 // the `Attest` type with `version` and a random `salt`
+)
+
+const zeroBytes32 = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+var eip712DomainType = []apitypes.Type{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+}
+
+// attestationFields are the fields shared by all versions, in the order of the EAS SDK
+var attestationFields = []apitypes.Type{
+	{Name: "schema", Type: "bytes32"},
+	{Name: "recipient", Type: "address"},
+	{Name: "time", Type: "uint64"},
+	{Name: "expirationTime", Type: "uint64"},
+	{Name: "revocable", Type: "bool"},
+	{Name: "refUID", Type: "bytes32"},
+	{Name: "data", Type: "bytes"},
+}
+
+// PrimaryType returns the EIP-712 primary type of attestations of the version
+func (v Version) PrimaryType() string {
+	if v == Version0 {
+		return "Attestation"
+	}
+	return "Attest"
+}
+
+// Types returns the EIP-712 types of attestations of the version, `EIP712Domain` included.
+// The version 1 has a `nonce` string, it has been signed by this SDK since the first release.
+func (v Version) Types() apitypes.Types {
+	var fields []apitypes.Type
+	switch v {
+	case Version0:
+		fields = append(fields, attestationFields...)
+	case Version1:
+		fields = append(fields, apitypes.Type{Name: "version", Type: "uint16"}, apitypes.Type{Name: "nonce", Type: "string"}) // TODO: should be uint256
+		fields = append(fields, attestationFields...)
+	default:
+		fields = append(fields, apitypes.Type{Name: "version", Type: "uint16"})
+		fields = append(fields, attestationFields...)
+		fields = append(fields, apitypes.Type{Name: "salt", Type: "bytes32"})
+	}
+	return apitypes.Types{
+		"EIP712Domain":  append([]apitypes.Type(nil), eip712DomainType...),
+		v.PrimaryType(): fields,
+	}
+}
+
+// VersionOf returns the version of the attestation `typedData`, the legacy version is told by its primary type,
+// the others by the `version` of the message
+func VersionOf(typedData *apitypes.TypedData) (Version, error) {
+	switch typedData.PrimaryType {
+	case "Attestation":
+		return Version0, nil
+	case "Attest":
+	default:
+		return 0, fmt.Errorf("unsupported primary type %q", typedData.PrimaryType)
+	}
+	v, err := strconv.ParseUint(messageString(typedData.Message, "version"), 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version: %w", err)
+	}
+	if v != uint64(Version1) && v != uint64(Version2) {
+		return 0, fmt.Errorf("unsupported version %d", v)
+	}
+	return Version(v), nil
+}
+
+// Attestation is the message of an off-chain attestation
+type Attestation struct {
+	Schema         string // the UID of the schema
+	Recipient      string
+	Time           uint64
+	ExpirationTime uint64 // 0 for no expiration
+	Revocable      bool   // if the schema is not revocable, it MUST be false
+	RefUID         string // zero if empty
+	Data           string // the encoded data of the schema
+	Salt           string // only for `Version2`, random if empty
+}
+
+// NewTypedData returns the EIP-712 typed data of `attestation` of `version` on `domain`, so it can be signed by `SignOffChainAttestation`
+func NewTypedData(version Version, domain apitypes.TypedDataDomain, attestation *Attestation) (*apitypes.TypedData, error) {
+	refUID := attestation.RefUID
+	if refUID == "" {
+		refUID = zeroBytes32
+	}
+	message := apitypes.TypedDataMessage{
+		"schema":         attestation.Schema,
+		"recipient":      attestation.Recipient,
+		"time":           strconv.FormatUint(attestation.Time, 10),
+		"expirationTime": strconv.FormatUint(attestation.ExpirationTime, 10),
+		"revocable":      attestation.Revocable,
+		"refUID":         refUID,
+		"data":           attestation.Data,
+	}
+	switch version {
+	case Version0:
+	case Version1:
+		message["version"] = "1" // TODO: should be uint16, when is string https://polygon-mumbai.easscan.org/tools will not verify success
+		message["nonce"] = "0"
+	case Version2:
+		salt := attestation.Salt
+		if salt == "" {
+			b := make([]byte, 32)
+			if _, err := rand.Read(b); err != nil {
+				return nil, fmt.Errorf("generate salt error: %w", err)
+			}
+			salt = hexutil.Encode(b)
+		}
+		message["version"] = "2"
+		message["salt"] = salt
+	default:
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+	return &apitypes.TypedData{
+		Types:       version.Types(),
+		PrimaryType: version.PrimaryType(),
+		Domain:      domain,
+		Message:     message,
+	}, nil
+}
+
+// messageString returns the field `key` of `message` as a string, numbers are decoded as float64 from JSON by the Node SDK
+func messageString(message apitypes.TypedDataMessage, key string) string {
+	switch v := message[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,111 @@
+package offchain
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+func TestVersionOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		typedData *apitypes.TypedData
+		want      Version
+		wantErr   bool
+	}{
+		{
+			name:      "version 0",
+			typedData: &apitypes.TypedData{PrimaryType: "Attestation", Message: apitypes.TypedDataMessage{}},
+			want:      Version0,
+		},
+		{
+			name:      "version 1",
+			typedData: &apitypes.TypedData{PrimaryType: "Attest", Message: apitypes.TypedDataMessage{"version": "1"}},
+			want:      Version1,
+		},
+		{
+			name:      "version 2 by Node SDK",
+			typedData: &apitypes.TypedData{PrimaryType: "Attest", Message: apitypes.TypedDataMessage{"version": float64(2)}},
+			want:      Version2,
+		},
+		{
+			name:      "unsupported version",
+			typedData: &apitypes.TypedData{PrimaryType: "Attest", Message: apitypes.TypedDataMessage{"version": "3"}},
+			wantErr:   true,
+		},
+		{
+			name:      "no version",
+			typedData: &apitypes.TypedData{PrimaryType: "Attest", Message: apitypes.TypedDataMessage{}},
+			wantErr:   true,
+		},
+		{
+			name:      "unsupported primary type",
+			typedData: &apitypes.TypedData{PrimaryType: "Mail", Message: apitypes.TypedDataMessage{"version": "1"}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VersionOf(tt.typedData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VersionOf() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("VersionOf() got = %v, want = %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTypedData(t *testing.T) {
+	now := time.Unix(1704249694, 0)
+	attestation := &Attestation{
+		Schema:         schemaUID,
+		Recipient:      recipient,
+		Time:           uint64(now.Unix()),
+		ExpirationTime: uint64(now.Add(time.Minute).Unix()),
+		Revocable:      true,
+		Data:           typedDataMessage["data"].(string),
+	}
+	for _, version := range []Version{Version0, Version1, Version2} {
+		typedData, err := NewTypedData(version, typedDataDomain, attestation)
+		if err != nil {
+			t.Fatalf("NewTypedData(%d) error = %v", version, err)
+		}
+		sig, err := SignOffChainAttestation(context.Background(), NewKeySigner(privateKey), typedData)
+		if err != nil {
+			t.Fatalf("SignOffChainAttestation(%d) error = %v", version, err)
+		}
+
+		// the proof is verified after a JSON round trip, like it's sent to a verifier
+		b, err := json.Marshal(sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Sig
+		if err = json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		expectTypedData := &apitypes.TypedData{Types: version.Types(), PrimaryType: version.PrimaryType(), Domain: typedDataDomain}
+		ok, err := VerifyOffChainAttestationAt(now, attester, recipient, expectTypedData, &got)
+		if err != nil || !ok {
+			t.Errorf("VerifyOffChainAttestationAt(%d) = %v, error = %v", version, ok, err)
+		}
+
+		// the salt is a part of the UID of the version 2
+		if version == Version2 {
+			got.Message["salt"] = zeroBytes32
+			if _, err = VerifyOffChainAttestationAt(now, attester, recipient, expectTypedData, &got); err == nil {
+				t.Errorf("VerifyOffChainAttestationAt(%d) with another salt, want error", version)
+			}
+		}
+	}
+
+	if _, err := NewTypedData(Version(3), typedDataDomain, attestation); err == nil {
+		t.Errorf("NewTypedData(3) want error")
+	}
+}
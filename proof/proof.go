@@ -1,55 +1,15 @@
 package proof
 
 import (
-	"crypto/ecdsa"
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/Taoist-Labs/see-auth-go/autherr"
 	"github.com/Taoist-Labs/see-auth-go/proof/offchain"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/math"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/signer/core/apitypes"
-)
-
-const (
-	// --- polygon mumbai ---
-	chainId            = 80001
-	easContractAddress = "0xaEF4103A04090071165F78D45D83A0C0782c2B2a"
-	easVersion         = "1.2.0"
-	schemaUID          = "0x32275eb98dcb8f82848adef9fa52311cc9e83bc6fdb34c5f46ac4b8d957ad3d9"
-)
-
-// ------ ------ ------ ------ ------ ------ ------ ------ ------
-
-var (
-	primaryType = "Attest"
-	types       = apitypes.Types{
-		"EIP712Domain": []apitypes.Type{
-			{Name: "name", Type: "string"},
-			{Name: "version", Type: "string"},
-			{Name: "chainId", Type: "uint256"},
-			{Name: "verifyingContract", Type: "address"},
-		},
-		"Attest": []apitypes.Type{
-			{Name: "version", Type: "uint16"},
-			{Name: "nonce", Type: "string"}, // TODO: should be uint256
-			{Name: "schema", Type: "bytes32"},
-			{Name: "recipient", Type: "address"},
-			{Name: "time", Type: "uint64"},
-			{Name: "expirationTime", Type: "uint64"},
-			{Name: "revocable", Type: "bool"},
-			{Name: "refUID", Type: "bytes32"},
-			{Name: "data", Type: "bytes"},
-		},
-	}
-	typedDataDomain = apitypes.TypedDataDomain{
-		Name:              "EAS Attestation",
-		Version:           easVersion,
-		ChainId:           math.NewHexOrDecimal256(chainId),
-		VerifyingContract: easContractAddress,
-	}
 )
 
 //type OffChainAttestationParams struct {
@@ -68,48 +28,129 @@ type Proof struct {
 	Signer string        `json:"signer"`
 }
 
-func Sign(recipient string, proofLifetime time.Duration, schemaData *SchemaData, privateKey string) (string, error) {
-	key, err := crypto.HexToECDSA(privateKey)
+// Parse parses a proof generated by `Sign`, the proof is not verified
+func Parse(proof string) (*Proof, error) {
+	var p Proof
+	err := json.Unmarshal([]byte(proof), &p)
+	if err != nil {
+		return nil, autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid proof", err)
+	}
+	if p.Sig == nil || p.Sig.TypedData == nil {
+		return nil, autherr.New(autherr.CodeMalformed, "Proof Error: proof has no sig")
+	}
+	return &p, nil
+}
+
+// EncodeURL encodes `proof` as a shareable URL of EASScan without the host, see `offchain.EncodeURL`
+func EncodeURL(proof string) (string, error) {
+	p, err := Parse(proof)
 	if err != nil {
 		return "", err
 	}
+	return offchain.EncodeURL(p.Sig, p.Signer)
+}
 
-	encodeData, err := offchain.SchemaEncode(schemaAbiTypes, []any{schemaData.Signature, common.HexToAddress(schemaData.Wallet), schemaData.Vendor})
+// DecodeURL decodes a shareable URL of EASScan into a proof, so it can be passed to `Verify`
+func DecodeURL(u string) (string, error) {
+	sig, signer, err := offchain.DecodeURL(u)
+	if err != nil {
+		return "", autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid url", err)
+	}
+	p, err := json.Marshal(&Proof{
+		Sig:    sig,
+		Signer: signer,
+	})
 	if err != nil {
 		return "", err
 	}
-	typedDataMessage := apitypes.TypedDataMessage{
-		"recipient":      recipient,
-		"time":           fmt.Sprintf("%d", time.Now().UTC().Unix()),                    // Unix timestamp of current time
-		"expirationTime": fmt.Sprintf("%d", time.Now().UTC().Add(proofLifetime).Unix()), // Unix timestamp of when attestation expires. (0 for no expiration)
-		"revocable":      true,                                                          // Be aware that if your schema is not revocable, this MUST be false
-		"version":        "1",                                                           // TODO: should be uint16, when is string https://polygon-mumbai.easscan.org/tools will not verify success
-		"nonce":          "0",
-		"schema":         schemaUID,
-		"refUID":         "0x0000000000000000000000000000000000000000000000000000000000000000",
-		"data":           encodeData,
+	return string(p), nil
+}
+
+// ExpirationTime returns when the proof expires
+func (p *Proof) ExpirationTime() (time.Time, error) {
+	expirationTime, err := strconv.ParseInt(fmt.Sprintf("%s", p.Sig.Message["expirationTime"]), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(expirationTime, 0), nil
+}
+
+// Sign issues a proof on `DefaultDeployment`
+func Sign(recipient string, proofLifetime time.Duration, schemaData *SchemaData, privateKey string) (string, error) {
+	return SignAt(time.Now(), recipient, proofLifetime, schemaData, privateKey)
+}
+
+// SignAt is like `Sign`, but the proof is issued at `now` instead of the current time
+func SignAt(now time.Time, recipient string, proofLifetime time.Duration, schemaData *SchemaData, privateKey string) (string, error) {
+	signer, err := offchain.NewHexKeySigner(privateKey)
+	if err != nil {
+		return "", err
 	}
+	return SignWith(context.Background(), now, recipient, proofLifetime, schemaData, signer)
+}
+
+// SignWith is like `SignAt`, but the proof is signed by `signer`, so the key can be kept in a keystore or by a remote signer
+func SignWith(ctx context.Context, now time.Time, recipient string, proofLifetime time.Duration, schemaData *SchemaData, signer Signer) (string, error) {
+	return (&Issuer{Deployment: DefaultDeployment}).Sign(ctx, now, recipient, proofLifetime, schemaData, signer)
+}
+
+// Signer signs proofs, see `offchain.Signer`
+type Signer = offchain.Signer
+
+// Issuer issues proofs on an EAS deployment
+type Issuer struct {
+	Deployment Deployment
+	Version    *offchain.Version // the version of issued proofs, `offchain.Version1` if it's nil
+}
 
-	typedData := &apitypes.TypedData{
-		Types:       types,
-		PrimaryType: primaryType,
-		Domain:      typedDataDomain,
-		Message:     typedDataMessage,
+// Sign issues a SeeAuth proof at `now` signed by `signer`, the EIP-712 domain and the schema are decided by the deployment of the issuer
+func (i *Issuer) Sign(ctx context.Context, now time.Time, recipient string, proofLifetime time.Duration, schemaData *SchemaData, signer Signer) (string, error) {
+	// EVM wallets use the schema of the deployment, other wallets (e.g. Solana) keep the wallet as a string
+	schemaUID := i.Deployment.SchemaUID
+	if !common.IsHexAddress(schemaData.Wallet) {
+		if i.Deployment.NonEVMSchemaUID == "" {
+			return "", fmt.Errorf("deployment %s doesn't support non-EVM wallet %s", i.Deployment.Name, schemaData.Wallet)
+		}
+		schemaUID = i.Deployment.NonEVMSchemaUID
 	}
+	return i.SignSchema(ctx, now, recipient, proofLifetime, schemaUID, schemaData, signer)
+}
 
-	sig, err := offchain.SignOffChainAttestation(key, typedData)
+// SignSchema issues a proof of the registered schema `schemaUID` at `now` signed by `signer`,
+// `data` is a value or a pointer of the data type of the schema, see `RegisterSchema`
+func (i *Issuer) SignSchema(ctx context.Context, now time.Time, recipient string, proofLifetime time.Duration, schemaUID string, data any, signer Signer) (string, error) {
+	schema, ok := LookupSchema(schemaUID)
+	if !ok {
+		return "", fmt.Errorf("schema %s not registered", schemaUID)
+	}
+	encodeData, err := schema.encode(data)
+	if err != nil {
+		return "", err
+	}
+	version := offchain.Version1
+	if i.Version != nil {
+		version = *i.Version
+	}
+	typedData, err := offchain.NewTypedData(version, i.Deployment.TypedDataDomain(), &offchain.Attestation{
+		Schema:         schemaUID,
+		Recipient:      recipient,
+		Time:           uint64(now.UTC().Unix()),
+		ExpirationTime: uint64(now.UTC().Add(proofLifetime).Unix()),
+		Revocable:      true, // Be aware that if your schema is not revocable, this MUST be false
+		Data:           encodeData,
+	})
 	if err != nil {
 		return "", err
 	}
 
-	publicKey := key.Public()
-	publicKeyECDSA, _ := publicKey.(*ecdsa.PublicKey)
-	address := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
-	// fmt.Println(address)
+	sig, err := offchain.SignOffChainAttestation(ctx, signer, typedData)
+	if err != nil {
+		return "", err
+	}
 
 	p, err := json.Marshal(&Proof{
 		Sig:    sig,
-		Signer: address,
+		Signer: signer.Address().Hex(),
 	})
 	if err != nil {
 		return "", err
@@ -118,43 +159,31 @@ func Sign(recipient string, proofLifetime time.Duration, schemaData *SchemaData,
 	return string(p), nil
 }
 
+// Verify verifies a proof issued on one of `DefaultDeployments()` and signed by `attester`,
+// it returns false without error when the proof is valid but signed by another address
 func Verify(attester, recipient, proof string) (bool, *SchemaData, error) {
-	var p Proof
-	err := json.Unmarshal([]byte(proof), &p)
-	if err != nil {
-		return false, nil, err
-	}
+	return VerifyAt(time.Now(), attester, recipient, proof)
+}
 
-	expectTypedData := &apitypes.TypedData{
-		Types:       types,
-		PrimaryType: primaryType,
-		Domain:      typedDataDomain,
-		Message:     nil, // this field not verify, so it can be nil
+// VerifyAt is like `Verify`, but the expiration of the proof is checked against `now` instead of the current time
+func VerifyAt(now time.Time, attester, recipient, proof string) (bool, *SchemaData, error) {
+	if attester == "0x0000000000000000000000000000000000000000" {
+		return false, nil, autherr.New(autherr.CodeAttesterMismatch, "Proof Error: attester is zero address")
 	}
 
-	isValid, err := offchain.VerifyOffChainAttestation(attester, recipient, expectTypedData, p.Sig)
+	result, err := (&Verifier{Attesters: AttesterSet{{Address: attester}}}).Verify(now, recipient, proof)
+	if autherr.CodeOf(err) == autherr.CodeAttesterMismatch {
+		return false, nil, nil
+	}
 	if err != nil {
 		return false, nil, err
 	}
-	if isValid {
-		encodeData, err := offchain.SchemaDecode(schemaAbiTypes, fmt.Sprintf("%s", p.Sig.Message["data"]))
-		if err != nil {
-			return false, nil, err
-		}
-		return true, &SchemaData{
-			Signature: fmt.Sprintf("%s", encodeData[0]),
-			Wallet:    fmt.Sprintf("%s", encodeData[1]),
-			Vendor:    fmt.Sprintf("%s", encodeData[2]),
-		}, nil
-	} else {
-		return false, nil, nil
-	}
+	return true, result.SchemaData, nil
 }
 
 // ------ ------ ------ ------ ------ ------ ------ ------ ------
 
-var schemaAbiTypes = []string{"string", "address", "string"}
-
+// SchemaData is the data of the SeeAuth schemas
 type SchemaData struct {
 	Signature string `json:"signature"`
 	Wallet    string `json:"wallet"`
@@ -18,6 +18,11 @@ var (
 		Wallet:    "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
 		Vendor:    "os+",
 	}
+	solanaSchemaData = &SchemaData{
+		Signature: "0x123214hsdkf",
+		Wallet:    "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV",
+		Vendor:    "os+",
+	}
 )
 
 func TestSign(t *testing.T) {
@@ -64,6 +69,7 @@ func TestVerify(t *testing.T) {
 		attester      string
 		recipient     string
 		proofLifetime time.Duration
+		schemaData    *SchemaData
 	}
 	tests := []struct {
 		name           string
@@ -75,17 +81,34 @@ func TestVerify(t *testing.T) {
 		{
 			name: "ok",
 			args: args{
-				attester:  attester,
-				recipient: recipient,
+				attester:      attester,
+				recipient:     recipient,
+				proofLifetime: proofLifetime,
 			},
 			wantOk:         true,
 			wantSchemaData: schemaData,
 			wantErr:        false,
 		},
+		{
+			name: "non-EVM wallet",
+			args: args{
+				attester:      attester,
+				recipient:     recipient,
+				proofLifetime: proofLifetime,
+				schemaData:    solanaSchemaData,
+			},
+			wantOk:         true,
+			wantSchemaData: solanaSchemaData,
+			wantErr:        false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			proof, err := Sign(tt.args.recipient, tt.args.proofLifetime, schemaData, privateKey)
+			data := tt.args.schemaData
+			if data == nil {
+				data = schemaData
+			}
+			proof, err := Sign(tt.args.recipient, tt.args.proofLifetime, data, privateKey)
 			if err != nil {
 				t.Errorf("Sign() error = %v", err)
 				return
@@ -105,3 +128,30 @@ func TestVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeURL(t *testing.T) {
+	proof, err := Sign(recipient, proofLifetime, schemaData, privateKey)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	u, err := EncodeURL(proof)
+	if err != nil {
+		t.Fatalf("EncodeURL() error = %v", err)
+	}
+
+	decoded, err := DecodeURL("https://polygon.easscan.org" + u)
+	if err != nil {
+		t.Fatalf("DecodeURL() error = %v", err)
+	}
+	gotOk, gotSchemaData, err := Verify(attester, recipient, decoded)
+	if err != nil || !gotOk {
+		t.Fatalf("Verify() gotOk = %v, error = %v", gotOk, err)
+	}
+	if !reflect.DeepEqual(gotSchemaData, schemaData) {
+		t.Errorf("Verify() gotSchemaData = %v, want = %v", gotSchemaData, schemaData)
+	}
+
+	if _, err = DecodeURL("https://polygon.easscan.org/offchain/url/#attestation=invalid"); err == nil {
+		t.Errorf("DecodeURL() want error")
+	}
+}
@@ -0,0 +1,211 @@
+package proof
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Taoist-Labs/see-auth-go/proof/offchain"
+)
+
+// Schema is an EAS schema which proofs can be issued and verified with
+type Schema struct {
+	UID       string       // the UID of the schema registered on EAS
+	Schema    string       // the EAS schema string, e.g. `string signature,address wallet,string vendor`
+	Resolver  string       // the resolver of the schema, the zero address if it has no resolver
+	Revocable bool         // whether attestations of the schema are revocable
+	Type      reflect.Type // the struct type of the data, a schema field is the struct field of the same `eas` or `json` name, or the same name
+}
+
+const (
+	seeAuthSchema       = "string signature,address wallet,string vendor"
+	seeAuthNonEVMSchema = "string signature,string wallet,string vendor"
+	zeroAddress         = "0x0000000000000000000000000000000000000000"
+)
+
+// built-in schemas, their data is a `SchemaData`
+var (
+	SeeAuthSchema       = Schema{UID: seeAuthSchemaUID, Schema: seeAuthSchema, Resolver: zeroAddress, Revocable: true, Type: reflect.TypeOf(SchemaData{})}
+	SeeAuthNonEVMSchema = Schema{UID: seeAuthNonEVMSchemaUID, Schema: seeAuthNonEVMSchema, Resolver: zeroAddress, Revocable: true, Type: reflect.TypeOf(SchemaData{})}
+)
+
+// legacySchemas are the schemas of UIDs which are not derived from them, keyed by the UID, they are kept to verify proofs issued before.
+//
+// The on-chain UID of PolygonMumbai does NOT match the schema its data is decoded with, on purpose:
+// the UID is the one of `address wallet,string vendor` (revocable, without resolver), but the first releases of this SDK
+// issued proofs under it with the data of the 3-field SeeAuth schema (`string signature,address wallet,string vendor`).
+// Decoding by the 2-field schema of the UID would reject all of those proofs, so don't "fix" the pair.
+// Only the exact pair is exempt, any other schema registered by the UID is checked
+var legacySchemas = map[string]string{
+	strings.ToLower(PolygonMumbai.SchemaUID): seeAuthSchema,
+}
+
+var (
+	schemasMu sync.RWMutex
+	schemas   = map[string]Schema{}
+)
+
+func init() {
+	// the legacy UID of PolygonMumbai is decoded as the SeeAuth schema, though it's the UID of another schema, see `legacySchemas`
+	for _, s := range []Schema{SeeAuthSchema, SeeAuthNonEVMSchema, {UID: PolygonMumbai.SchemaUID, Schema: seeAuthSchema}} {
+		if err := RegisterSchema(s.UID, s.Schema, &SchemaData{}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SchemaOption configures a schema registered by `RegisterSchema`
+type SchemaOption func(*Schema)
+
+// WithSchemaResolver sets the resolver of the schema, the default is no resolver
+func WithSchemaResolver(resolver string) SchemaOption {
+	return func(s *Schema) {
+		s.Resolver = resolver
+	}
+}
+
+// WithSchemaRevocable sets whether attestations of the schema are revocable, the default is true
+func WithSchemaRevocable(revocable bool) SchemaOption {
+	return func(s *Schema) {
+		s.Revocable = revocable
+	}
+}
+
+// RegisterSchema adds a schema to the registry, it replaces the schema of the same UID.
+// `v` is a value or a pointer of the struct type of the data, each field of `schema` must have a struct field.
+// `uid` must be the UID derived from the schema, the resolver and the revocability, see `offchain.ComputeSchemaUID`
+func RegisterSchema(uid, schema string, v any, opts ...SchemaOption) error {
+	typ := reflect.TypeOf(v)
+	if typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fmt.Errorf("schema %s: data type %T is not a struct", uid, v)
+	}
+	s := Schema{UID: strings.ToLower(uid), Schema: schema, Resolver: zeroAddress, Revocable: true, Type: typ}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if err := s.Check(); err != nil {
+		return err
+	}
+
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+
+	schemas[s.UID] = s
+	return nil
+}
+
+// LookupSchema returns the registered schema of `uid`
+func LookupSchema(uid string) (Schema, bool) {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+
+	s, ok := schemas[strings.ToLower(uid)]
+	return s, ok
+}
+
+// Schemas returns all registered schemas ordered by UID
+func Schemas() []Schema {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+
+	ss := make([]Schema, 0, len(schemas))
+	for _, s := range schemas {
+		ss = append(ss, s)
+	}
+	sort.Slice(ss, func(i, j int) bool { return ss[i].UID < ss[j].UID })
+	return ss
+}
+
+// Check checks the UID is derived from the schema, and each field of the schema has a struct field of the data type
+func (s *Schema) Check() error {
+	if legacy, ok := legacySchemas[strings.ToLower(s.UID)]; !ok || legacy != s.Schema {
+		if uid := offchain.ComputeSchemaUID(s.Schema, s.Resolver, s.Revocable); !strings.EqualFold(uid, s.UID) {
+			return fmt.Errorf("schema %s: UID of %q is %s", s.UID, s.Schema, uid)
+		}
+	}
+	_, _, err := s.fields()
+	return err
+}
+
+// fields parses the schema string and matches each field to a struct field of the data type
+func (s *Schema) fields() ([]offchain.SchemaField, []int, error) {
+	fields, err := offchain.ParseSchema(s.Schema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("schema %s: %w", s.UID, err)
+	}
+	indexes := make([]int, len(fields))
+	for i, f := range fields {
+		index, ok := structField(s.Type, f.Name)
+		if !ok {
+			return nil, nil, fmt.Errorf("schema %s: %s has no field %s", s.UID, s.Type, f.Name)
+		}
+		indexes[i] = index
+	}
+	return fields, indexes, nil
+}
+
+// structField finds the exported field of `typ` named `name` by the `eas` tag (see `offchain.Marshal`) or the `json` tag,
+// or by the name case-insensitively
+func structField(typ reflect.Type, name string) (int, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if parts := strings.Fields(f.Tag.Get("eas")); len(parts) == 2 && parts[1] == name {
+			return i, true
+		}
+		if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag == name {
+			return i, true
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.IsExported() && f.Tag.Get("eas") == "" && f.Tag.Get("json") == "" && strings.EqualFold(f.Name, name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// encode encodes `data`, a value or a pointer of the data type, to the hex of the ABI encoding, see `offchain.EncodeFields`
+func (s *Schema) encode(data any) (string, error) {
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Type() != s.Type {
+		return "", fmt.Errorf("schema %s: data type %T, expect %s", s.UID, data, s.Type)
+	}
+	fields, indexes, err := s.fields()
+	if err != nil {
+		return "", err
+	}
+	values := make([]any, len(fields))
+	for i, index := range indexes {
+		values[i] = v.Field(index).Interface()
+	}
+	return offchain.EncodeFields(fields, values)
+}
+
+// decode decodes the hex of the ABI encoding to a pointer of a new value of the data type, see `offchain.DecodeFields`
+func (s *Schema) decode(data string) (any, error) {
+	fields, indexes, err := s.fields()
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.New(s.Type)
+	dsts := make([]any, len(fields))
+	for i, index := range indexes {
+		dsts[i] = v.Elem().Field(index).Addr().Interface()
+	}
+	if err = offchain.DecodeFields(fields, data, dsts); err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
@@ -0,0 +1,114 @@
+package proof
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Taoist-Labs/see-auth-go/proof/offchain"
+)
+
+// membership is the data of a schema which carries roles, the SNS name and the membership tier
+type membership struct {
+	Signature string   `json:"signature"`
+	Wallet    string   `json:"wallet"`
+	Vendor    string   `json:"vendor"`
+	Roles     []string `json:"roles"`
+	Name      string   `eas:"string sns"`
+	Tier      uint8
+}
+
+const (
+	membershipSchema    = "string signature,address wallet,string vendor,string[] roles,string sns,uint8 tier"
+	membershipSchemaUID = "0x69a1cde073536ddcc82d0ddecfb4a05ba3f17f5824c694f6769197710be23aa6"
+)
+
+func TestRegisterSchema(t *testing.T) {
+	resolver := "0x00000000000000000000000000000000000000aa"
+	tests := []struct {
+		name    string
+		uid     string // derived from the schema without resolver if empty
+		schema  string
+		v       any
+		opts    []SchemaOption
+		wantErr bool
+	}{
+		{name: "struct", schema: membershipSchema, v: membership{}, wantErr: false},
+		{name: "pointer", schema: membershipSchema, v: &membership{}, wantErr: false},
+		{name: "resolver", uid: offchain.ComputeSchemaUID(membershipSchema, resolver, false), schema: membershipSchema, v: membership{}, opts: []SchemaOption{WithSchemaResolver(resolver), WithSchemaRevocable(false)}, wantErr: false},
+		{name: "resolver not match", uid: offchain.ComputeSchemaUID(membershipSchema, resolver, true), schema: membershipSchema, v: membership{}, wantErr: true},
+		{name: "revocable not match", uid: membershipSchemaUID, schema: membershipSchema, v: membership{}, opts: []SchemaOption{WithSchemaRevocable(false)}, wantErr: true},
+		{name: "schema not match", uid: seeAuthSchemaUID, schema: membershipSchema, v: membership{}, wantErr: true},
+		{name: "legacy UID of another schema", uid: PolygonMumbai.SchemaUID, schema: membershipSchema, v: membership{}, wantErr: true},
+		{name: "not struct", schema: "string name", v: "", wantErr: true},
+		{name: "nil", schema: "string name", v: nil, wantErr: true},
+		{name: "field not found", schema: "string signature,uint256 score", v: membership{}, wantErr: true},
+		{name: "invalid type", schema: "strin signature", v: membership{}, wantErr: true},
+		{name: "invalid field", schema: "string", v: membership{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid := tt.uid
+			if uid == "" {
+				uid = offchain.ComputeSchemaUID(tt.schema, zeroAddress, true)
+			}
+			if err := RegisterSchema(uid, tt.schema, tt.v, tt.opts...); (err != nil) != tt.wantErr {
+				t.Errorf("RegisterSchema() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	s, ok := LookupSchema(SeeAuthSchema.UID)
+	if !ok || s.Type != reflect.TypeOf(SchemaData{}) {
+		t.Errorf("LookupSchema() got = %+v, %v", s, ok)
+	}
+	// the legacy UID is not derived from its schema
+	s, ok = LookupSchema(PolygonMumbai.SchemaUID)
+	if !ok || s.Schema != seeAuthSchema {
+		t.Errorf("LookupSchema() of legacy UID got = %+v, %v", s, ok)
+	}
+	if uid := offchain.ComputeSchemaUID("address wallet,string vendor", zeroAddress, true); uid != PolygonMumbai.SchemaUID {
+		t.Errorf("ComputeSchemaUID() of legacy schema = %v, want = %v", uid, PolygonMumbai.SchemaUID)
+	}
+}
+
+func TestIssuer_SignSchema(t *testing.T) {
+	if err := RegisterSchema(membershipSchemaUID, membershipSchema, membership{}); err != nil {
+		t.Fatalf("RegisterSchema() error = %v", err)
+	}
+	signer, err := offchain.NewHexKeySigner(privateKey)
+	if err != nil {
+		t.Fatalf("NewHexKeySigner() error = %v", err)
+	}
+	now := time.Now()
+	data := &membership{Signature: "0x1234", Wallet: attester, Vendor: "os+", Roles: []string{"builder", "node"}, Name: "alice.seedao", Tier: 2}
+
+	proof, err := (&Issuer{Deployment: DefaultDeployment}).SignSchema(context.Background(), now, recipient, proofLifetime, membershipSchemaUID, data, signer)
+	if err != nil {
+		t.Fatalf("SignSchema() error = %v", err)
+	}
+
+	// only SeeAuth schemas are accepted by default
+	if _, err = (&Verifier{Attesters: AttesterSet{{Address: attester}}}).Verify(now, recipient, proof); err == nil {
+		t.Errorf("Verify() of not accepted schema error = nil")
+	}
+
+	got, err := (&Verifier{Attesters: AttesterSet{{Address: attester}}, Schemas: []string{membershipSchemaUID}}).Verify(now, recipient, proof)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Data, data) {
+		t.Errorf("Verify() data = %+v, want = %+v", got.Data, data)
+	}
+	if got.SchemaData != nil {
+		t.Errorf("Verify() schema data = %+v, want = nil", got.SchemaData)
+	}
+
+	if _, err = (&Issuer{Deployment: DefaultDeployment}).SignSchema(context.Background(), now, recipient, proofLifetime, membershipSchemaUID, schemaData, signer); err == nil {
+		t.Errorf("SignSchema() of another type error = nil")
+	}
+	if _, err = (&Issuer{Deployment: DefaultDeployment}).SignSchema(context.Background(), now, recipient, proofLifetime, "0x2222222222222222222222222222222222222222222222222222222222222222", data, signer); err == nil {
+		t.Errorf("SignSchema() of unregistered schema error = nil")
+	}
+}
@@ -0,0 +1,121 @@
+package proof
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Taoist-Labs/see-auth-go/autherr"
+	"github.com/Taoist-Labs/see-auth-go/proof/offchain"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Verifier verifies proofs signed by trusted attesters on accepted EAS deployments
+type Verifier struct {
+	Attesters   AttesterSet
+	Deployments []Deployment // accepted deployments, `DefaultDeployments()` if empty
+	Schemas     []string     // UIDs of accepted schemas, only the SeeAuth schemas of the deployment if empty
+}
+
+// Result is the result of a successful verification
+type Result struct {
+	Attester   Attester         // the attester which signed the proof
+	Deployment Deployment       // the deployment which the proof is issued on
+	Schema     Schema           // the schema of the proof
+	Version    offchain.Version // the version of the off-chain attestation
+	Data       any              // a pointer to the data of the proof, its type is the data type of the schema
+	SchemaData *SchemaData      // the data if it's a `SchemaData`, i.e. the proof is of a SeeAuth schema
+}
+
+// VerifyAttesters is like `VerifyAt`, but the proof can be signed by any attester in `attesters` which is trusted at `now`.
+// It returns the attester which signed the proof.
+func VerifyAttesters(now time.Time, attesters AttesterSet, recipient, proof string) (*Attester, *SchemaData, error) {
+	result, err := (&Verifier{Attesters: attesters}).Verify(now, recipient, proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &result.Attester, result.SchemaData, nil
+}
+
+// Verify verifies the proof at `now`, it must be issued on an accepted deployment,
+// and signed by an attester which is trusted at `now`
+func (v *Verifier) Verify(now time.Time, recipient, proof string) (*Result, error) {
+	p, err := Parse(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, ok := v.matchDeployment(p.Sig.Domain)
+	if !ok {
+		return nil, autherr.New(autherr.CodeMalformed, "Proof Error: domain not match")
+	}
+	schemaUID := fmt.Sprintf("%s", p.Sig.Message["schema"])
+	if !v.acceptSchema(&deployment, schemaUID) {
+		return nil, autherr.New(autherr.CodeMalformed, "Proof Error: schema not match")
+	}
+	schema, ok := LookupSchema(schemaUID)
+	if !ok {
+		return nil, autherr.New(autherr.CodeMalformed, "Proof Error: schema not registered")
+	}
+
+	// the version of the proof decides the expected types
+	version, err := offchain.VersionOf(p.Sig.TypedData)
+	if err != nil {
+		return nil, autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid version", err)
+	}
+	expectTypedData := &apitypes.TypedData{
+		Types:       version.Types(),
+		PrimaryType: version.PrimaryType(),
+		Domain:      p.Sig.Domain, // matched above
+		Message:     nil,          // this field not verify, so it can be nil
+	}
+
+	signer, err := offchain.RecoverOffChainAttestationSigner(now, recipient, expectTypedData, p.Sig)
+	if err != nil {
+		return nil, err
+	}
+	attester, ok := v.Attesters.Match(signer, now)
+	if !ok {
+		return nil, autherr.New(autherr.CodeAttesterMismatch, fmt.Sprintf("Proof Error: signer %s is not a trusted attester", signer))
+	}
+
+	data, err := schema.decode(fmt.Sprintf("%s", p.Sig.Message["data"]))
+	if err != nil {
+		return nil, autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid schema data", err)
+	}
+	schemaData, _ := data.(*SchemaData)
+	return &Result{
+		Attester:   *attester,
+		Deployment: deployment,
+		Schema:     schema,
+		Version:    version,
+		Data:       data,
+		SchemaData: schemaData,
+	}, nil
+}
+
+func (v *Verifier) acceptSchema(deployment *Deployment, uid string) bool {
+	if len(v.Schemas) == 0 {
+		return strings.EqualFold(uid, deployment.SchemaUID) ||
+			deployment.NonEVMSchemaUID != "" && strings.EqualFold(uid, deployment.NonEVMSchemaUID)
+	}
+	for _, schema := range v.Schemas {
+		if strings.EqualFold(uid, schema) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Verifier) matchDeployment(domain apitypes.TypedDataDomain) (Deployment, bool) {
+	deployments := v.Deployments
+	if len(deployments) == 0 {
+		deployments = DefaultDeployments()
+	}
+	for i := range deployments {
+		if deployments[i].matchDomain(domain) {
+			return deployments[i], true
+		}
+	}
+	return Deployment{}, false
+}
@@ -0,0 +1,43 @@
+package seeauth
+
+import (
+	"context"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// ReplayStore remembers which proofs have been used, so that every proof can be used only once.
+// Implementations must be safe for concurrent use; to protect several replicas, they must share the same store.
+type ReplayStore interface {
+	// CheckAndSet atomically marks `key` as used for `ttl`.
+	// It returns false if `key` is already marked and the mark has not expired yet.
+	CheckAndSet(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// MemoryReplayStore is a process-local ReplayStore, it only protects a single replica
+type MemoryReplayStore struct {
+	cache *cache.Cache
+}
+
+// NewMemoryReplayStore creates a MemoryReplayStore,
+// expired marks are deleted every `cleanupInterval`, it is not necessary to delete them immediately,
+// because expired marks are ignored by `CheckAndSet` anyway. we prefer performance nor memory-use
+func NewMemoryReplayStore(cleanupInterval time.Duration) *MemoryReplayStore {
+	return &MemoryReplayStore{
+		cache: cache.New(cache.NoExpiration, cleanupInterval),
+	}
+}
+
+func (s *MemoryReplayStore) CheckAndSet(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	// a non-positive `ttl` means never expire for go-cache, but the mark would be expired at once here
+	if ttl <= 0 {
+		_, found := s.cache.Get(key)
+		return !found, nil
+	}
+	// `Add` fails when an unexpired item exists, and it holds the lock of the cache between the check and the set
+	if err := s.cache.Add(key, struct{}{}, ttl); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
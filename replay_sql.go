@@ -0,0 +1,95 @@
+package seeauth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SQLPlaceholder returns the placeholder of the n-th (starts from 1) query argument
+type SQLPlaceholder func(n int) string
+
+var (
+	// QuestionPlaceholder is used by MySQL and SQLite
+	QuestionPlaceholder SQLPlaceholder = func(int) string { return "?" }
+	// DollarPlaceholder is used by PostgreSQL
+	DollarPlaceholder SQLPlaceholder = func(n int) string { return "$" + strconv.Itoa(n) }
+)
+
+// SQLReplayStore is a ReplayStore backed by `database/sql`, replicas sharing the same database share the marks.
+// The uniqueness of the primary key makes `CheckAndSet` atomic.
+type SQLReplayStore struct {
+	db          *sql.DB
+	table       string
+	placeholder SQLPlaceholder
+	now         func() time.Time
+}
+
+// NewSQLReplayStore creates a SQLReplayStore,
+// `table` is used in queries as it is, so it must not come from user input.
+// The table is expected to be created by `CreateTable` or to have the same columns.
+func NewSQLReplayStore(db *sql.DB, table string, placeholder SQLPlaceholder) *SQLReplayStore {
+	return &SQLReplayStore{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+	}
+}
+
+// WithClock sets the function used to get the current time when marks are set and expired, `time.Now` if it's not set.
+// It should be the clock of the Authenticators using the store, see `WithClock` of the Authenticator
+func (s *SQLReplayStore) WithClock(now func() time.Time) *SQLReplayStore {
+	s.now = now
+	return s
+}
+
+func (s *SQLReplayStore) currentTime() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
+// CreateTable creates the table if it does not exist
+func (s *SQLReplayStore) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (id VARCHAR(255) NOT NULL PRIMARY KEY, expires_at BIGINT NOT NULL)", s.table))
+	return err
+}
+
+func (s *SQLReplayStore) CheckAndSet(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := s.currentTime()
+
+	// an expired mark must not block the key
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = %s AND expires_at <= %s",
+		s.table, s.placeholder(1), s.placeholder(2)), key, now.Unix())
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (id, expires_at) VALUES (%s, %s)",
+		s.table, s.placeholder(1), s.placeholder(2)), key, now.Add(ttl).Unix())
+	if err == nil {
+		return true, nil
+	}
+
+	// drivers report primary key violations differently, so check whether the key is there instead of parsing `err`
+	var count int
+	if e := s.db.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = %s",
+		s.table, s.placeholder(1)), key).Scan(&count); e == nil && count > 0 {
+		return false, nil
+	}
+	return false, err
+}
+
+// Purge deletes all expired marks, it returns the number of deleted marks
+func (s *SQLReplayStore) Purge(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE expires_at <= %s",
+		s.table, s.placeholder(1)), s.currentTime().Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
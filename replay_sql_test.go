@@ -0,0 +1,79 @@
+//go:build cgo
+
+package seeauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Taoist-Labs/see-auth-go/common"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newSQLiteReplayStore(t *testing.T) *SQLReplayStore {
+	db, err := sql.Open("sqlite3", "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	store := NewSQLReplayStore(db, "seeauth_replay", QuestionPlaceholder)
+	if err := store.CreateTable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSQLReplayStore(t *testing.T) {
+	testReplayStore(t, newSQLiteReplayStore(t))
+}
+
+func TestSQLReplayStore_Purge(t *testing.T) {
+	ctx := context.Background()
+	store := newSQLiteReplayStore(t)
+
+	_, _ = store.CheckAndSet(ctx, "expired", -time.Second)
+	_, _ = store.CheckAndSet(ctx, "alive", time.Minute)
+
+	got, err := store.Purge(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1 {
+		t.Errorf("Purge() got = %d, want = 1", got)
+	}
+}
+
+func TestSQLReplayStore_Clock(t *testing.T) {
+	ctx := context.Background()
+	past := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := newSQLiteReplayStore(t).WithClock(func() time.Time { return past })
+
+	// the mark expires a minute after the time of the clock, which is long before the current time
+	if fresh, err := store.CheckAndSet(ctx, "clock", time.Minute); err != nil || !fresh {
+		t.Fatalf("CheckAndSet() first = %v, %v, want = true", fresh, err)
+	}
+	if fresh, err := store.CheckAndSet(ctx, "clock", time.Minute); err != nil || fresh {
+		t.Errorf("CheckAndSet() reuse = %v, %v, want = false", fresh, err)
+	}
+	if got, err := store.Purge(ctx); err != nil || got != 0 {
+		t.Errorf("Purge() got = %d, %v, want = 0", got, err)
+	}
+}
+
+// TestAuthenticator_ReuseSQL is like `TestAuthenticator_Reuse`, the proof is marked in the database
+func TestAuthenticator_ReuseSQL(t *testing.T) {
+	a := NewAuthenticator(WithAttester(stagingAttester), WithBlockNumberSource(common.StaticBlockNumber(0)), WithReplayStore(newSQLiteReplayStore(t)))
+	seeAuth := issueSeeAuth(t, a)
+
+	if _, err := a.SeeDAOAuth(recipient, seeAuth); err != nil {
+		t.Fatalf("SeeDAOAuth() first error = %v", err)
+	}
+	if _, err := a.SeeDAOAuth(recipient, seeAuth); !errors.Is(err, ErrReplay) {
+		t.Fatalf("SeeDAOAuth() reuse error = %v, want = %v", err, ErrReplay)
+	}
+}
@@ -0,0 +1,65 @@
+package seeauth
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testReplayStore checks the behaviors every ReplayStore must have
+func testReplayStore(t *testing.T, store ReplayStore) {
+	ctx := context.Background()
+
+	if ok, err := store.CheckAndSet(ctx, "nonce1", time.Minute); err != nil || !ok {
+		t.Fatalf("CheckAndSet() first = %v, %v, want = true", ok, err)
+	}
+	if ok, err := store.CheckAndSet(ctx, "nonce1", time.Minute); err != nil || ok {
+		t.Fatalf("CheckAndSet() reuse = %v, %v, want = false", ok, err)
+	}
+	if ok, err := store.CheckAndSet(ctx, "nonce2", time.Minute); err != nil || !ok {
+		t.Fatalf("CheckAndSet() other key = %v, %v, want = true", ok, err)
+	}
+
+	// an expired mark doesn't block the key
+	if ok, err := store.CheckAndSet(ctx, "nonce3", -time.Second); err != nil || !ok {
+		t.Fatalf("CheckAndSet() expired first = %v, %v, want = true", ok, err)
+	}
+	if ok, err := store.CheckAndSet(ctx, "nonce3", time.Minute); err != nil || !ok {
+		t.Fatalf("CheckAndSet() after expired = %v, %v, want = true", ok, err)
+	}
+
+	// only one of concurrent callers wins
+	var wins int32
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, _ := store.CheckAndSet(ctx, "nonce4", time.Minute); ok {
+				atomic.AddInt32(&wins, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if wins != 1 {
+		t.Fatalf("CheckAndSet() concurrent wins = %d, want = 1", wins)
+	}
+}
+
+func TestMemoryReplayStore(t *testing.T) {
+	testReplayStore(t, NewMemoryReplayStore(time.Minute))
+}
+
+func TestWithReplayStore(t *testing.T) {
+	// the store may be shared by Authenticators, it's not changed by them
+	store := NewSQLReplayStore(nil, "seeauth_replay", QuestionPlaceholder)
+	a := NewAuthenticator(WithReplayStore(store), WithClock(func() time.Time { return time.Unix(1704249694, 0) }))
+	if a.replayStore != store {
+		t.Errorf("NewAuthenticator() replay store = %v, want = %v", a.replayStore, store)
+	}
+	if store.now != nil {
+		t.Errorf("NewAuthenticator() set its clock to the replay store")
+	}
+}
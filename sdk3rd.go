@@ -1,64 +1,130 @@
 package seeauth
 
 import (
-	"errors"
-	"strconv"
+	"context"
+	"fmt"
+	"time"
 
-	"github.com/Taoist-Labs/see-auth-go/common"
+	"github.com/Taoist-Labs/see-auth-go/autherr"
 	"github.com/Taoist-Labs/see-auth-go/proof"
 	"github.com/Taoist-Labs/see-auth-go/signature"
-	"github.com/patrickmn/go-cache"
 )
 
-// in-memory cache for proof-used-flag
-// `defaultExpiration` is proofLifetime, but `cleanUpInterval` is proofLifetime*6 for performance,
-// because even proof-used-flag is expired, it is not necessary to delete it immediately. we prefer performance nor memory-use
-var defaultCache = cache.New(proofLifetime, proofLifetime*6)
-
-// SeeDAOAuth authenticates a SeeAuth service
+// SeeDAOAuth authenticates a SeeAuth service with the default Authenticator
 // `recipient` parameter is
 // `seeAuth` parameter is the SeeAuth object, you can parse from the request body commonly.
 // It returns the wallet address if the authentication is successful,otherwise it returns an error
 func SeeDAOAuth(recipient string, seeAuth *SeeAuth) (string, error) {
-	// ---> get proof-used-flag from cache
-	key := seeAuth.Signature.Nonce // use `signature.nonce` as KEY
-	if _, found := defaultCache.Get(key); found {
-		return "", errors.New("Reuse proof")
+	return defaultAuthenticator.SeeDAOAuth(recipient, seeAuth)
+}
+
+// SeeDAOAuthContext is like `SeeDAOAuth`, but it stops waiting for the RPC and the replay store when `ctx` is done
+func SeeDAOAuthContext(ctx context.Context, recipient string, seeAuth *SeeAuth) (string, error) {
+	return defaultAuthenticator.SeeDAOAuthContext(ctx, recipient, seeAuth)
+}
+
+// SeeDAOAuth authenticates a SeeAuth service, see the package-level `SeeDAOAuth`
+func (a *Authenticator) SeeDAOAuth(recipient string, seeAuth *SeeAuth) (string, error) {
+	return a.SeeDAOAuthContext(context.Background(), recipient, seeAuth)
+}
+
+// SeeDAOAuthContext is like `SeeDAOAuth`, but it stops waiting for the RPC and the replay store when `ctx` is done
+func (a *Authenticator) SeeDAOAuthContext(ctx context.Context, recipient string, seeAuth *SeeAuth) (string, error) {
+	result, err := a.Authenticate(ctx, recipient, seeAuth)
+	if err != nil {
+		return "", err
+	}
+	return result.Wallet, nil
+}
+
+// AuthResult is the result of a successful authentication
+type AuthResult struct {
+	Wallet     string
+	Attester   proof.Attester   // the attester which signed the proof
+	Deployment proof.Deployment // the EAS deployment which the proof is issued on
+	SignerType signature.SignerType
+}
+
+// Authenticate is like `SeeDAOAuthContext` with the default Authenticator, but it returns the details of the authentication
+func Authenticate(ctx context.Context, recipient string, seeAuth *SeeAuth) (*AuthResult, error) {
+	return defaultAuthenticator.Authenticate(ctx, recipient, seeAuth)
+}
+
+// Authenticate is like `SeeDAOAuthContext`, but it returns the details of the authentication
+func (a *Authenticator) Authenticate(ctx context.Context, recipient string, seeAuth *SeeAuth) (*AuthResult, error) {
+	if seeAuth == nil || seeAuth.Signature == nil || seeAuth.Proof == nil {
+		return nil, autherr.New(autherr.CodeMalformed, "seeAuth has no signature or proof")
 	}
 
 	// verify latest-block-number
-	number, _ := strconv.Atoi(seeAuth.Signature.Nonce[16:])
-	numberOnChain, _ := common.GetLatestBlockNumber()
-	if number != 0 && numberOnChain != 0 && int(numberOnChain)-number > 5 {
-		return "", errors.New("block number too old")
+	err := a.checkBlockNumber(ctx, seeAuth.Signature.Nonce)
+	if err != nil {
+		return nil, err
 	}
 
 	// proofing proof
-	ok, schemaData, err := proof.Verify(attester, recipient, seeAuth.Proof.Proof)
+	now := a.now()
+	verifier := &proof.Verifier{Attesters: a.attesters, Deployments: a.deployments}
+	verified, err := verifier.Verify(now, recipient, seeAuth.Proof.Proof)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if !ok {
-		return "", errors.New("Invalid proof")
+	schemaData := verified.SchemaData
+	if schemaData == nil {
+		return nil, autherr.New(autherr.CodeMalformed, "Proof Error: not a SeeAuth proof")
 	}
 
-	// ---> set proof-used-flag from cache
-	defaultCache.Set(key, struct{}{}, proofLifetime)
-
 	// signature in proof must be same to signature in signature
 	if schemaData.Signature != seeAuth.Signature.Signature {
-		return "", errors.New("Invalid signature")
+		return nil, autherr.New(autherr.CodeSignatureMismatch, "Invalid signature")
+	}
+
+	// the signature must be requested by an allowed domain
+	if err = a.checkDomain(seeAuth.Signature.Message); err != nil {
+		return nil, err
+	}
+
+	// the message must satisfy the message policy
+	if err = a.checkMessagePolicy(seeAuth.Signature.Message); err != nil {
+		return nil, err
 	}
 
 	// verify signature
-	err = signature.Verify(seeAuth.Wallet, seeAuth.Signature.Domain, seeAuth.Signature.Nonce, seeAuth.Signature.Message, seeAuth.Signature.Signature)
+	signed, err := a.verifySignature(ctx, seeAuth.WalletName, seeAuth.Wallet, seeAuth.Signature.Domain, seeAuth.Signature.Nonce, seeAuth.Signature.Message, seeAuth.Signature.Signature)
 	if err != nil {
-		return "", errors.New("Invalid signature")
+		return nil, signatureError(err)
 	}
 
 	if schemaData.Wallet != seeAuth.Wallet {
-		return "", errors.New("Invalid payload")
+		return nil, autherr.New(autherr.CodePayloadMismatch, "Invalid payload")
+	}
+
+	// ---> check and set proof-used-flag, only when everything else is valid, so an invalid request can't burn a proof
+	// the flag lives as long as the proof, `expirationTime` is inclusive, so keep it one more second
+	p, err := proof.Parse(seeAuth.Proof.Proof)
+	if err != nil {
+		return nil, err
+	}
+	expirationTime, err := p.ExpirationTime()
+	if err != nil {
+		return nil, autherr.Wrap(autherr.CodeMalformed, "Proof Error: invalid expiration time", err)
+	}
+	key := seeAuth.Signature.Nonce // use `signature.nonce` as KEY
+	fresh, err := a.replayStore.CheckAndSet(ctx, key, expirationTime.Sub(now)+time.Second)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("check proof-used-flag: %w", ctx.Err())
+		}
+		return nil, err
+	}
+	if !fresh {
+		return nil, autherr.New(autherr.CodeReplay, "Reuse proof")
 	}
 
-	return seeAuth.Wallet, nil
+	return &AuthResult{
+		Wallet:     seeAuth.Wallet,
+		Attester:   verified.Attester,
+		Deployment: verified.Deployment,
+		SignerType: signed.SignerType,
+	}, nil
 }
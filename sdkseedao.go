@@ -1,20 +1,35 @@
 package seeauth
 
 import (
-	"errors"
-	"fmt"
-	"strconv"
+	"context"
 
-	"github.com/Taoist-Labs/see-auth-go/common"
 	"github.com/Taoist-Labs/see-auth-go/proof"
-	"github.com/Taoist-Labs/see-auth-go/signature"
-	"github.com/spruceid/siwe-go"
+	"github.com/Taoist-Labs/see-auth-go/proof/offchain"
 )
 
+// GenerateNonce generates a nonce with the default Authenticator
 func GenerateNonce() string {
-	nonce := siwe.GenerateNonce()
-	number, _ := common.GetLatestBlockNumber() // when something wrong, `number` is 0
-	return fmt.Sprintf("%s%d", nonce, number)
+	return defaultAuthenticator.GenerateNonce()
+}
+
+// GenerateNonceContext is like `GenerateNonce`, but it stops waiting for the RPC when `ctx` is done
+func GenerateNonceContext(ctx context.Context) (string, error) {
+	return defaultAuthenticator.GenerateNonceContext(ctx)
+}
+
+// GenerateNonce generates a nonce, which binds the SIWE message to the latest block, see `Nonce`
+func (a *Authenticator) GenerateNonce() string {
+	nonce, _ := a.GenerateNonceContext(context.Background())
+	return nonce
+}
+
+// GenerateNonceContext is like `GenerateNonce`, but it stops waiting for the RPC when `ctx` is done
+func (a *Authenticator) GenerateNonceContext(ctx context.Context) (string, error) {
+	number, err := a.latestBlockNumber(ctx) // when something wrong, `number` is 0
+	if err != nil {
+		return "", err
+	}
+	return NewNonce(number, a.chainID).String(), nil
 }
 
 type (
@@ -29,26 +44,60 @@ type (
 	ProofParams struct {
 		Recipient  string
 		Schema     *proof.SchemaData
-		PrivateKey string
+		PrivateKey string       // the hex private key of the attester, it's ignored when `Signer` is set
+		Signer     proof.Signer // signs the proof, e.g. a keystore or a remote signer, see `offchain.Signer`
 	}
 )
 
+// Auth verifies the signature and generates a proof with the default Authenticator
 func Auth(signatureParams *SignatureParams, proofParams *ProofParams) (*SeeAuth, error) {
+	return defaultAuthenticator.Auth(signatureParams, proofParams)
+}
+
+// AuthContext is like `Auth`, but it stops waiting for the RPC when `ctx` is done
+func AuthContext(ctx context.Context, signatureParams *SignatureParams, proofParams *ProofParams) (*SeeAuth, error) {
+	return defaultAuthenticator.AuthContext(ctx, signatureParams, proofParams)
+}
+
+// Auth verifies the signature and generates a proof, see the package-level `Auth`
+func (a *Authenticator) Auth(signatureParams *SignatureParams, proofParams *ProofParams) (*SeeAuth, error) {
+	return a.AuthContext(context.Background(), signatureParams, proofParams)
+}
+
+// AuthContext is like `Auth`, but it stops waiting for the RPC when `ctx` is done
+func (a *Authenticator) AuthContext(ctx context.Context, signatureParams *SignatureParams, proofParams *ProofParams) (*SeeAuth, error) {
 	// verify latest-block-number
-	number, _ := strconv.Atoi(signatureParams.Nonce[16:])
-	numberOnChain, _ := common.GetLatestBlockNumber()
-	if number != 0 && numberOnChain != 0 && int(numberOnChain)-number > 5 {
-		return nil, errors.New("block number too old")
+	err := a.checkBlockNumber(ctx, signatureParams.Nonce)
+	if err != nil {
+		return nil, err
 	}
 
-	// verify signature
-	err := signature.Verify(signatureParams.Wallet, signatureParams.Domain, signatureParams.Nonce, signatureParams.Message, signatureParams.Signature)
+	// the signature must be requested by an allowed domain
+	err = a.checkDomain(signatureParams.Message)
 	if err != nil {
 		return nil, err
 	}
 
+	// the message must satisfy the message policy
+	if err = a.checkMessagePolicy(signatureParams.Message); err != nil {
+		return nil, err
+	}
+
+	// verify signature
+	_, err = a.verifySignature(ctx, signatureParams.WalletName, signatureParams.Wallet, signatureParams.Domain, signatureParams.Nonce, signatureParams.Message, signatureParams.Signature)
+	if err != nil {
+		return nil, signatureError(err)
+	}
+
 	// generating proof
-	p, err := proof.Sign(proofParams.Recipient, proofLifetime, proofParams.Schema, proofParams.PrivateKey)
+	signer := proofParams.Signer
+	if signer == nil {
+		signer, err = offchain.NewHexKeySigner(proofParams.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p, err := (&proof.Issuer{Deployment: a.deployments[0]}).Sign(ctx, a.now(), proofParams.Recipient, a.proofLifetime, proofParams.Schema, signer)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,91 @@
+package signature
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+)
+
+// base58Alphabet is the alphabet of Bitcoin and Solana
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Indexes = func() [256]int {
+	var indexes [256]int
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		indexes[base58Alphabet[i]] = i
+	}
+	return indexes
+}()
+
+// base58Decode decodes a base58 string, each leading '1' is a leading zero byte
+func base58Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty base58 string")
+	}
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	for i := 0; i < len(s); i++ {
+		index := base58Indexes[s[i]]
+		if index < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(index)))
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// base58Encode encodes `b` to a base58 string
+func base58Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(b)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, '1')
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// base58CheckDecode decodes a base58check string, it returns the version byte and the payload
+func base58CheckDecode(s string) (byte, []byte, error) {
+	b, err := base58Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(b) < 5 {
+		return 0, nil, errors.New("base58check string too short")
+	}
+	checksum := doubleSHA256(b[:len(b)-4])
+	if !bytes.Equal(checksum[:4], b[len(b)-4:]) {
+		return 0, nil, errors.New("invalid base58check checksum")
+	}
+	return b[0], b[1 : len(b)-4], nil
+}
+
+// base58CheckEncode encodes `payload` with the version byte to a base58check string
+func base58CheckEncode(version byte, payload []byte) string {
+	b := append([]byte{version}, payload...)
+	checksum := doubleSHA256(b)
+	return base58Encode(append(b, checksum[:4]...))
+}
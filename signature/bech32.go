@@ -0,0 +1,144 @@
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// bech32Charset is the alphabet of BIP-173
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32 checksum constants, BIP-173 bech32 and BIP-350 bech32m
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Decode decodes a bech32 or bech32m string, it returns the HRP, the 5-bit data without checksum and the checksum constant
+func bech32Decode(s string) (string, []byte, uint32, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, errors.New("mixed case bech32 string")
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, 0, errors.New("invalid bech32 separator")
+	}
+	hrp := s[:sep]
+	data := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		index := strings.IndexByte(bech32Charset, s[i])
+		if index < 0 {
+			return "", nil, 0, errors.New("invalid bech32 character")
+		}
+		data = append(data, byte(index))
+	}
+	checksum := bech32Polymod(append(bech32HRPExpand(hrp), data...))
+	if checksum != bech32Const && checksum != bech32mConst {
+		return "", nil, 0, errors.New("invalid bech32 checksum")
+	}
+	return hrp, data[:len(data)-6], checksum, nil
+}
+
+// bech32Encode encodes the 5-bit `data` with the checksum constant `variant`
+func bech32Encode(hrp string, data []byte, variant uint32) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ variant
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// convertBits regroups `data` from `from` bits to `to` bits per byte
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	acc, bits := uint32(0), uint(0)
+	maxv := uint32(1)<<to - 1
+	var out []byte
+	for _, b := range data {
+		if uint32(b)>>from != 0 {
+			return nil, errors.New("invalid data range")
+		}
+		acc = acc<<from | uint32(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+// decodeSegwitAddress decodes a segwit address of BIP-173 and BIP-350, it returns the HRP, the witness version and program
+func decodeSegwitAddress(address string) (string, byte, []byte, error) {
+	hrp, data, variant, err := bech32Decode(address)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if len(data) < 1 || data[0] > 16 {
+		return "", 0, nil, errors.New("invalid witness version")
+	}
+	version := data[0]
+	if (version == 0) != (variant == bech32Const) {
+		return "", 0, nil, fmt.Errorf("invalid checksum variant of witness version %d", version)
+	}
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	if len(program) < 2 || len(program) > 40 || (version == 0 && len(program) != 20 && len(program) != 32) {
+		return "", 0, nil, fmt.Errorf("invalid witness program length %d", len(program))
+	}
+	return hrp, version, program, nil
+}
+
+// encodeSegwitAddress encodes a witness program to a segwit address
+func encodeSegwitAddress(hrp string, version byte, program []byte) string {
+	data, _ := convertBits(program, 8, 5, true)
+	variant := uint32(bech32mConst)
+	if version == 0 {
+		variant = bech32Const
+	}
+	return bech32Encode(hrp, append([]byte{version}, data...), variant)
+}
@@ -0,0 +1,394 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// BitcoinVerifier verifies Sign-In-With-Bitcoin signatures of wallets like Unisat and Xverse.
+// Both the legacy `signmessage` signature (P2PKH, and P2WPKH of BIP-137) and the BIP-322 simple signature (P2WPKH and P2TR) are accepted
+type BitcoinVerifier struct {
+	// Policy is what the message must satisfy, `DefaultMessagePolicy` is used if it's nil
+	Policy *MessagePolicy
+}
+
+// Verify verifies the Sign-In-With-Bitcoin `message` is signed by `wallet`, the address of the wallet.
+// The signature is base64, it's a 65 bytes compact signature of `signmessage`, or a BIP-322 witness stack
+func (v *BitcoinVerifier) Verify(ctx context.Context, wallet, domain, nonce, message, signature string) (*Result, error) {
+	m, err := ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if m.Blockchain != "Bitcoin" {
+		return nil, fmt.Errorf("not a Sign-In-With-Bitcoin message: %s account", m.Blockchain)
+	}
+	if err = checkFields(v.Policy, m, domain, nonce); err != nil {
+		return nil, err
+	}
+	if m.Address != wallet {
+		return nil, errors.New("signer not match")
+	}
+
+	address, err := parseBitcoinAddress(wallet)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+
+	// a witness stack starts with the number of items, which is never a header of `signmessage`
+	if len(sig) == 65 && sig[0] >= 27 && sig[0] <= 42 {
+		err = verifySignMessage(address, message, sig)
+	} else {
+		err = verifyBIP322Simple(address, message, sig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Wallet: wallet, SignerType: SignerEOA}, nil
+}
+
+type bitcoinAddressType int
+
+const (
+	bitcoinP2PKH bitcoinAddressType = iota
+	bitcoinP2WPKH
+	bitcoinP2TR
+)
+
+// bitcoinAddress is a decoded Bitcoin address, `program` is the public key hash, or the x-only output key of P2TR
+type bitcoinAddress struct {
+	typ     bitcoinAddressType
+	program []byte
+}
+
+// parseBitcoinAddress parses a P2PKH, P2WPKH or P2TR address of mainnet, testnet or regtest
+func parseBitcoinAddress(address string) (*bitcoinAddress, error) {
+	if hrp, version, program, err := decodeSegwitAddress(address); err == nil {
+		if hrp != "bc" && hrp != "tb" && hrp != "bcrt" {
+			return nil, fmt.Errorf("invalid Bitcoin address: unknown network %s", hrp)
+		}
+		switch {
+		case version == 0 && len(program) == 20:
+			return &bitcoinAddress{typ: bitcoinP2WPKH, program: program}, nil
+		case version == 1 && len(program) == 32:
+			return &bitcoinAddress{typ: bitcoinP2TR, program: program}, nil
+		default:
+			return nil, fmt.Errorf("unsupported Bitcoin address: witness version %d", version)
+		}
+	}
+
+	version, payload, err := base58CheckDecode(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Bitcoin address: %w", err)
+	}
+	if (version != 0x00 && version != 0x6f) || len(payload) != 20 {
+		return nil, fmt.Errorf("unsupported Bitcoin address: version %d", version)
+	}
+	return &bitcoinAddress{typ: bitcoinP2PKH, program: payload}, nil
+}
+
+// scriptPubKey returns the output script which pays to the address
+func (a *bitcoinAddress) scriptPubKey() []byte {
+	switch a.typ {
+	case bitcoinP2PKH:
+		return p2pkhScript(a.program)
+	case bitcoinP2WPKH:
+		return append([]byte{0x00, 0x14}, a.program...)
+	default:
+		return append([]byte{0x51, 0x20}, a.program...)
+	}
+}
+
+func p2pkhScript(pubKeyHash []byte) []byte {
+	script := append([]byte{0x76, 0xa9, 0x14}, pubKeyHash...)
+	return append(script, 0x88, 0xac)
+}
+
+// verifySignMessage verifies a compact signature of `signmessage`, the header of BIP-137 tells the address type.
+// 27-30 and 31-34 are P2PKH of uncompressed and compressed keys, 39-42 is P2WPKH
+func verifySignMessage(address *bitcoinAddress, message string, sig []byte) error {
+	header := sig[0]
+	switch {
+	case header >= 39:
+		if address.typ != bitcoinP2WPKH {
+			return errors.New("signature of P2WPKH, but the address is not")
+		}
+		header -= 8
+	case header >= 35:
+		return errors.New("P2SH-P2WPKH is not supported")
+	}
+	if address.typ == bitcoinP2TR {
+		return errors.New("P2TR address must be signed by BIP-322")
+	}
+
+	compact := append([]byte{header}, sig[1:]...)
+	publicKey, compressed, err := ecdsa.RecoverCompact(compact, signMessageHash(message))
+	if err != nil {
+		return fmt.Errorf("failed to recover public key: %w", err)
+	}
+	if address.typ == bitcoinP2WPKH && !compressed {
+		return errors.New("P2WPKH requires a compressed public key")
+	}
+	serialized := publicKey.SerializeUncompressed()
+	if compressed {
+		serialized = publicKey.SerializeCompressed()
+	}
+	if !bytes.Equal(hash160(serialized), address.program) {
+		return errors.New("signer not match")
+	}
+	return nil
+}
+
+// signMessageHash is the hash signed by `signmessage`
+func signMessageHash(message string) []byte {
+	var buf bytes.Buffer
+	writeVarBytes(&buf, []byte("Bitcoin Signed Message:\n"))
+	writeVarBytes(&buf, []byte(message))
+	return doubleSHA256(buf.Bytes())
+}
+
+// BIP-322 sighash types
+const (
+	sigHashDefault = 0x00
+	sigHashAll     = 0x01
+)
+
+// verifyBIP322Simple verifies a BIP-322 simple signature, which is the witness stack of the `to_sign` transaction
+func verifyBIP322Simple(address *bitcoinAddress, message string, sig []byte) error {
+	witness, err := parseWitness(sig)
+	if err != nil {
+		return err
+	}
+	scriptPubKey := address.scriptPubKey()
+	toSpend := bip322ToSpendTxID(message, scriptPubKey)
+
+	switch address.typ {
+	case bitcoinP2WPKH:
+		if len(witness) != 2 || len(witness[0]) < 2 {
+			return errors.New("invalid P2WPKH witness")
+		}
+		der, hashType := witness[0][:len(witness[0])-1], witness[0][len(witness[0])-1]
+		if hashType != sigHashAll {
+			return fmt.Errorf("unsupported sighash type %d", hashType)
+		}
+		if !bytes.Equal(hash160(witness[1]), address.program) {
+			return errors.New("signer not match")
+		}
+		publicKey, err := btcec.ParsePubKey(witness[1])
+		if err != nil {
+			return fmt.Errorf("invalid public key: %w", err)
+		}
+		s, err := ecdsa.ParseDERSignature(der)
+		if err != nil {
+			return fmt.Errorf("invalid signature: %w", err)
+		}
+		if !s.Verify(bip143SigHash(toSpend, p2pkhScript(address.program)), publicKey) {
+			return errors.New("signer not match")
+		}
+		return nil
+	case bitcoinP2TR:
+		if len(witness) != 1 {
+			return errors.New("invalid P2TR witness")
+		}
+		hashType := byte(sigHashDefault)
+		switch len(witness[0]) {
+		case 64:
+		case 65:
+			hashType = witness[0][64]
+			if hashType != sigHashAll {
+				return fmt.Errorf("unsupported sighash type %d", hashType)
+			}
+		default:
+			return fmt.Errorf("invalid schnorr signature length %d", len(witness[0]))
+		}
+		publicKey, err := schnorr.ParsePubKey(address.program)
+		if err != nil {
+			return fmt.Errorf("invalid output key: %w", err)
+		}
+		s, err := schnorr.ParseSignature(witness[0][:64])
+		if err != nil {
+			return fmt.Errorf("invalid signature: %w", err)
+		}
+		if !s.Verify(taprootSigHash(toSpend, scriptPubKey, hashType), publicKey) {
+			return errors.New("signer not match")
+		}
+		return nil
+	default:
+		return errors.New("P2PKH address must be signed by signmessage")
+	}
+}
+
+// bip322ToSpendTxID returns the ID of the virtual `to_spend` transaction of BIP-322, in internal byte order
+func bip322ToSpendTxID(message string, scriptPubKey []byte) []byte {
+	messageHash := taggedHash("BIP0322-signed-message", []byte(message))
+
+	var tx bytes.Buffer
+	writeUint32(&tx, 0) // version
+	writeVarInt(&tx, 1)
+	tx.Write(make([]byte, 32)) // prevout hash
+	writeUint32(&tx, 0xffffffff)
+	writeVarBytes(&tx, append([]byte{0x00, 0x20}, messageHash...)) // OP_0 PUSH32 message_hash
+	writeUint32(&tx, 0)                                            // sequence
+	writeVarInt(&tx, 1)
+	writeUint64(&tx, 0) // value
+	writeVarBytes(&tx, scriptPubKey)
+	writeUint32(&tx, 0) // lock time
+	return doubleSHA256(tx.Bytes())
+}
+
+// bip322ToSignOutput is the only output of the virtual `to_sign` transaction, 0 value and OP_RETURN
+var bip322ToSignOutput = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0x01, 0x6a}
+
+// bip143SigHash is the SIGHASH_ALL segwit v0 sighash of the `to_sign` transaction spending `to_spend`
+func bip143SigHash(toSpend, scriptCode []byte) []byte {
+	outpoint := append(append([]byte{}, toSpend...), 0, 0, 0, 0)
+
+	var preimage bytes.Buffer
+	writeUint32(&preimage, 0) // version
+	preimage.Write(doubleSHA256(outpoint))
+	preimage.Write(doubleSHA256([]byte{0, 0, 0, 0})) // sequences
+	preimage.Write(outpoint)
+	writeVarBytes(&preimage, scriptCode)
+	writeUint64(&preimage, 0) // amount
+	writeUint32(&preimage, 0) // sequence
+	preimage.Write(doubleSHA256(bip322ToSignOutput))
+	writeUint32(&preimage, 0) // lock time
+	writeUint32(&preimage, sigHashAll)
+	return doubleSHA256(preimage.Bytes())
+}
+
+// taprootSigHash is the BIP-341 key path sighash of the `to_sign` transaction spending `to_spend`
+func taprootSigHash(toSpend, scriptPubKey []byte, hashType byte) []byte {
+	outpoint := append(append([]byte{}, toSpend...), 0, 0, 0, 0)
+	var scriptPubKeys bytes.Buffer
+	writeVarBytes(&scriptPubKeys, scriptPubKey)
+
+	var preimage bytes.Buffer
+	preimage.WriteByte(0x00) // epoch
+	preimage.WriteByte(hashType)
+	writeUint32(&preimage, 0) // version
+	writeUint32(&preimage, 0) // lock time
+	preimage.Write(sha256Sum(outpoint))
+	preimage.Write(sha256Sum(make([]byte, 8))) // amounts
+	preimage.Write(sha256Sum(scriptPubKeys.Bytes()))
+	preimage.Write(sha256Sum([]byte{0, 0, 0, 0})) // sequences
+	preimage.Write(sha256Sum(bip322ToSignOutput))
+	preimage.WriteByte(0x00)  // spend type, key path without annex
+	writeUint32(&preimage, 0) // input index
+	return taggedHash("TapSighash", preimage.Bytes())
+}
+
+// parseWitness parses a serialized witness stack
+func parseWitness(b []byte) ([][]byte, error) {
+	r := bytes.NewReader(b)
+	n, err := readVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid witness: %w", err)
+	}
+	if n > uint64(len(b)) {
+		return nil, errors.New("invalid witness: too many items")
+	}
+	witness := make([][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		size, err := readVarInt(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid witness: %w", err)
+		}
+		if size > uint64(r.Len()) {
+			return nil, errors.New("invalid witness: item too long")
+		}
+		item := make([]byte, size)
+		_, _ = r.Read(item)
+		witness = append(witness, item)
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("invalid witness: trailing bytes")
+	}
+	return witness, nil
+}
+
+func readVarInt(r *bytes.Reader) (uint64, error) {
+	prefix, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	var size int
+	switch prefix {
+	case 0xfd:
+		size = 2
+	case 0xfe:
+		size = 4
+	case 0xff:
+		size = 8
+	default:
+		return uint64(prefix), nil
+	}
+	b := make([]byte, 8)
+	if n, _ := r.Read(b[:size]); n != size {
+		return 0, errors.New("unexpected end of varint")
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func writeVarInt(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n < 0xfd:
+		buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(0xfd)
+		_ = binary.Write(buf, binary.LittleEndian, uint16(n))
+	case n <= 0xffffffff:
+		buf.WriteByte(0xfe)
+		writeUint32(buf, uint32(n))
+	default:
+		buf.WriteByte(0xff)
+		writeUint64(buf, n)
+	}
+}
+
+func writeVarBytes(buf *bytes.Buffer, b []byte) {
+	writeVarInt(buf, uint64(len(b)))
+	buf.Write(b)
+}
+
+func writeUint32(buf *bytes.Buffer, n uint32) {
+	_ = binary.Write(buf, binary.LittleEndian, n)
+}
+
+func writeUint64(buf *bytes.Buffer, n uint64) {
+	_ = binary.Write(buf, binary.LittleEndian, n)
+}
+
+func sha256Sum(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+func doubleSHA256(b []byte) []byte {
+	return sha256Sum(sha256Sum(b))
+}
+
+// taggedHash is the tagged hash of BIP-340
+func taggedHash(tag string, msg []byte) []byte {
+	tagHash := sha256Sum([]byte(tag))
+	return sha256Sum(append(append(append([]byte{}, tagHash...), tagHash...), msg...))
+}
+
+func hash160(b []byte) []byte {
+	h := ripemd160.New()
+	h.Write(sha256Sum(b))
+	return h.Sum(nil)
+}
@@ -0,0 +1,197 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// test vectors of BIP-322
+const (
+	bip322P2WPKHAddress = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+	bip322P2TRAddress   = "bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3"
+)
+
+func TestBIP322Vectors(t *testing.T) {
+	if got := hex.EncodeToString(taggedHash("BIP0322-signed-message", []byte("Hello World"))); got != "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a" {
+		t.Errorf("message hash = %v", got)
+	}
+
+	tests := []struct {
+		name      string
+		address   string
+		message   string
+		signature string
+		wantErr   bool
+	}{
+		{name: "p2wpkh empty message", address: bip322P2WPKHAddress, message: "", signature: "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI="},
+		{name: "p2wpkh", address: bip322P2WPKHAddress, message: "Hello World", signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI="},
+		{name: "p2wpkh other message", address: bip322P2WPKHAddress, message: "", signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=", wantErr: true},
+		{name: "p2tr", address: bip322P2TRAddress, message: "Hello World", signature: "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ=="},
+		{name: "p2tr other message", address: bip322P2TRAddress, message: "", signature: "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := parseBitcoinAddress(tt.address)
+			if err != nil {
+				t.Fatalf("parseBitcoinAddress() error = %v", err)
+			}
+			sig, _ := base64.StdEncoding.DecodeString(tt.signature)
+			if err = verifyBIP322Simple(address, tt.message, sig); (err != nil) != tt.wantErr {
+				t.Errorf("verifyBIP322Simple() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// bitcoinKey is the private key of the BIP-322 test vectors, L3VFeEujGtevx9w18HD1fhRbCH67Az2dpCymeRE1SoPK6XQtaN2k
+func bitcoinKey(t *testing.T) *btcec.PrivateKey {
+	_, payload, err := base58CheckDecode("L3VFeEujGtevx9w18HD1fhRbCH67Az2dpCymeRE1SoPK6XQtaN2k")
+	if err != nil {
+		t.Fatalf("base58CheckDecode() error = %v", err)
+	}
+	key, _ := btcec.PrivKeyFromBytes(payload[:32])
+	return key
+}
+
+// signBitcoin builds a Sign-In-With-Bitcoin message of `address`, `sign` signs it
+func signBitcoin(address string, signatureLifetime time.Duration, sign func(message string) []byte) (message, signature string) {
+	m := &Message{
+		Domain:         tDomain,
+		Blockchain:     "Bitcoin",
+		Address:        address,
+		Statement:      tStatement,
+		URI:            tUri,
+		Version:        "1",
+		ChainID:        "000000000019d6689c085ae165831e93",
+		Nonce:          nonce,
+		IssuedAt:       time.Now().UTC().Format(time.RFC3339),
+		ExpirationTime: time.Now().UTC().Add(signatureLifetime).Format(time.RFC3339),
+	}
+	message = m.String()
+	return message, base64.StdEncoding.EncodeToString(sign(message))
+}
+
+func signMessageWith(key *btcec.PrivateKey, compressed bool, header byte) func(string) []byte {
+	return func(message string) []byte {
+		sig, _ := ecdsa.SignCompact(key, signMessageHash(message), compressed)
+		sig[0] += header
+		return sig
+	}
+}
+
+func bip322P2WPKHWith(key *btcec.PrivateKey) func(string) []byte {
+	return func(message string) []byte {
+		pubKeyHash := hash160(key.PubKey().SerializeCompressed())
+		toSpend := bip322ToSpendTxID(message, append([]byte{0x00, 0x14}, pubKeyHash...))
+		sig := append(ecdsa.Sign(key, bip143SigHash(toSpend, p2pkhScript(pubKeyHash))).Serialize(), sigHashAll)
+		var witness bytes.Buffer
+		writeVarInt(&witness, 2)
+		writeVarBytes(&witness, sig)
+		writeVarBytes(&witness, key.PubKey().SerializeCompressed())
+		return witness.Bytes()
+	}
+}
+
+func bip322P2TRWith(key *btcec.PrivateKey) func(string) []byte {
+	return func(message string) []byte {
+		scriptPubKey := append([]byte{0x51, 0x20}, schnorr.SerializePubKey(key.PubKey())...)
+		toSpend := bip322ToSpendTxID(message, scriptPubKey)
+		sig, _ := schnorr.Sign(key, taprootSigHash(toSpend, scriptPubKey, sigHashDefault))
+		var witness bytes.Buffer
+		writeVarInt(&witness, 1)
+		writeVarBytes(&witness, sig.Serialize())
+		return witness.Bytes()
+	}
+}
+
+func TestBitcoinVerifier_Verify(t *testing.T) {
+	key := bitcoinKey(t)
+	otherKey, _ := btcec.NewPrivateKey()
+	compressedHash := hash160(key.PubKey().SerializeCompressed())
+	p2pkh := base58CheckEncode(0x00, compressedHash)
+	uncompressedP2PKH := base58CheckEncode(0x00, hash160(key.PubKey().SerializeUncompressed()))
+	p2wpkh := encodeSegwitAddress("bc", 0, compressedHash)
+	p2tr := encodeSegwitAddress("bc", 1, schnorr.SerializePubKey(key.PubKey()))
+	if p2wpkh != bip322P2WPKHAddress {
+		t.Fatalf("p2wpkh = %v, want = %v", p2wpkh, bip322P2WPKHAddress)
+	}
+
+	tests := []struct {
+		name    string
+		wallet  string
+		sign    func(string) []byte
+		expired bool
+		wantErr bool
+	}{
+		{name: "legacy p2pkh", wallet: p2pkh, sign: signMessageWith(key, true, 0)},
+		{name: "legacy p2pkh uncompressed", wallet: uncompressedP2PKH, sign: signMessageWith(key, false, 0)},
+		{name: "legacy p2wpkh", wallet: p2wpkh, sign: signMessageWith(key, true, 0)},
+		{name: "legacy p2wpkh of BIP-137", wallet: p2wpkh, sign: signMessageWith(key, true, 8)},
+		{name: "legacy p2pkh signed by other", wallet: p2pkh, sign: signMessageWith(otherKey, true, 0), wantErr: true},
+		{name: "legacy compressed key of uncompressed address", wallet: uncompressedP2PKH, sign: signMessageWith(key, true, 0), wantErr: true},
+		{name: "legacy p2tr", wallet: p2tr, sign: signMessageWith(key, true, 0), wantErr: true},
+		{name: "bip322 p2wpkh", wallet: p2wpkh, sign: bip322P2WPKHWith(key)},
+		{name: "bip322 p2wpkh signed by other", wallet: p2wpkh, sign: bip322P2WPKHWith(otherKey), wantErr: true},
+		{name: "bip322 p2tr", wallet: p2tr, sign: bip322P2TRWith(key)},
+		{name: "bip322 p2tr signed by other", wallet: p2tr, sign: bip322P2TRWith(otherKey), wantErr: true},
+		{name: "bip322 p2pkh", wallet: p2pkh, sign: bip322P2WPKHWith(key), wantErr: true},
+		{name: "expired", wallet: p2wpkh, sign: bip322P2WPKHWith(key), expired: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lifetime := signatureLifetime
+			if tt.expired {
+				lifetime = -signatureLifetime
+			}
+			message, signature := signBitcoin(tt.wallet, lifetime, tt.sign)
+			got, err := (&BitcoinVerifier{}).Verify(context.Background(), tt.wallet, tDomain, nonce, message, signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Wallet != tt.wallet {
+				t.Errorf("Verify() wallet = %v, want = %v", got.Wallet, tt.wallet)
+			}
+		})
+	}
+
+	// a SIWE message is not accepted
+	evmMessage, evmSignature, _ := Sign(nonce, signatureLifetime, privateKey)
+	if _, err := (&BitcoinVerifier{}).Verify(context.Background(), wallet, tDomain, nonce, evmMessage, evmSignature); err == nil {
+		t.Errorf("Verify() of SIWE message error = nil")
+	}
+}
+
+func TestParseBitcoinAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantErr bool
+	}{
+		{name: "p2pkh", address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"},
+		{name: "p2pkh testnet", address: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"},
+		{name: "p2wpkh", address: bip322P2WPKHAddress},
+		{name: "p2wpkh uppercase", address: "BC1Q9VZA2E8X573NCZRLZMS0WVX3GSQJX7VAVGKX0L"},
+		{name: "p2tr", address: bip322P2TRAddress},
+		{name: "p2sh", address: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", wantErr: true},
+		{name: "p2wsh", address: "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", wantErr: true},
+		{name: "bad checksum", address: "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0m", wantErr: true},
+		{name: "p2tr with bech32 checksum", address: "bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx", wantErr: true},
+		{name: "other network", address: "ltc1q9vza2e8x573nczrlzms0wvx3gsqjx7vaxyz9p8", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseBitcoinAddress(tt.address); (err != nil) != tt.wantErr {
+				t.Errorf("parseBitcoinAddress() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
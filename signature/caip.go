@@ -0,0 +1,139 @@
+package signature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AccountID is a CAIP-10 account ID, e.g. `eip155:1:0xab16a96D359eC26a11e2C2b3d8f8B8942d5Bfcdb`
+type AccountID struct {
+	Namespace string // the CAIP-2 namespace, e.g. `eip155`, `solana` or `bip122`
+	Reference string // the CAIP-2 reference of the chain in the namespace, e.g. `1` of `eip155`
+	Address   string
+}
+
+var accountIDPattern = regexp.MustCompile(`^([-a-z0-9]{3,8}):([-_a-zA-Z0-9]{1,32}):([-.%a-zA-Z0-9]{1,128})$`)
+
+// ParseAccountID parses a CAIP-10 account ID
+func ParseAccountID(s string) (*AccountID, error) {
+	matches := accountIDPattern.FindStringSubmatch(s)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid CAIP-10 account ID %q", s)
+	}
+	return &AccountID{Namespace: matches[1], Reference: matches[2], Address: matches[3]}, nil
+}
+
+// IsAccountID reports whether `s` is a CAIP-10 account ID, EVM and Solana addresses are not
+func IsAccountID(s string) bool {
+	return accountIDPattern.MatchString(s)
+}
+
+func (a *AccountID) String() string {
+	return a.Namespace + ":" + a.Reference + ":" + a.Address
+}
+
+// NamespaceRegistry dispatches CAIP-122 messages to the verifier of the namespace of the CAIP-10 account ID.
+// It's a WalletVerifier of which the wallet is a CAIP-10 account ID
+type NamespaceRegistry struct {
+	mu        sync.RWMutex
+	verifiers map[string]WalletVerifier
+}
+
+// NewNamespaceRegistry creates an empty NamespaceRegistry
+func NewNamespaceRegistry() *NamespaceRegistry {
+	return &NamespaceRegistry{verifiers: make(map[string]WalletVerifier)}
+}
+
+// Register sets the verifier of `namespace`, it replaces the verifier registered before
+func (r *NamespaceRegistry) Register(namespace string, verifier WalletVerifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.verifiers[namespace] = verifier
+}
+
+// Lookup returns the verifier of `namespace`
+func (r *NamespaceRegistry) Lookup(namespace string) (WalletVerifier, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	verifier, ok := r.verifiers[namespace]
+	return verifier, ok
+}
+
+// Namespaces returns the registered namespaces in order
+func (r *NamespaceRegistry) Namespaces() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	namespaces := make([]string, 0, len(r.verifiers))
+	for namespace := range r.verifiers {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// Verify verifies the CAIP-122 `message` is signed by the CAIP-10 `accountID`.
+// The address and the chain ID of the message must be the address and the reference of the account ID,
+// then the message is verified by the verifier of the namespace with the address
+func (r *NamespaceRegistry) Verify(ctx context.Context, accountID, domain, nonce, message, signature string) (*Result, error) {
+	account, err := ParseAccountID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	verifier, ok := r.Lookup(account.Namespace)
+	if !ok {
+		return nil, fmt.Errorf("namespace %s not supported", account.Namespace)
+	}
+
+	m, err := ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if !sameAddress(account.Namespace, m.Address, account.Address) {
+		return nil, errors.New("signer not match")
+	}
+	if m.ChainID != account.Reference {
+		return nil, fmt.Errorf("chain %s of message not match account %s", m.ChainID, accountID)
+	}
+
+	// the address of the message is passed, the verifier may compare it exactly, e.g. EIP-55 of EVM addresses
+	result, err := verifier.Verify(ctx, m.Address, domain, nonce, message, signature)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Wallet: accountID, SignerType: result.SignerType}, nil
+}
+
+// sameAddress reports whether the addresses of `namespace` are the same,
+// EVM addresses are compared case-insensitively since CAIP-10 account IDs are often lowercase
+func sameAddress(namespace, a, b string) bool {
+	if namespace == "eip155" {
+		return common.IsHexAddress(a) && common.IsHexAddress(b) && common.HexToAddress(a) == common.HexToAddress(b)
+	}
+	return a == b
+}
+
+// DefaultNamespaces is the registry used by `VerifyAccount`, `eip155` (EOA only), `solana`, `bip122` and `cosmos` are built in
+var DefaultNamespaces = func() *NamespaceRegistry {
+	r := NewNamespaceRegistry()
+	r.Register("eip155", &Verifier{})
+	r.Register("solana", &SolanaVerifier{})
+	r.Register("bip122", &BitcoinVerifier{})
+	r.Register("cosmos", &CosmosVerifier{})
+	return r
+}()
+
+// RegisterNamespace sets the verifier of `namespace` in `DefaultNamespaces`
+func RegisterNamespace(namespace string, verifier WalletVerifier) {
+	DefaultNamespaces.Register(namespace, verifier)
+}
+
+// VerifyAccount verifies the CAIP-122 `message` is signed by the CAIP-10 `accountID` with `DefaultNamespaces`
+func VerifyAccount(ctx context.Context, accountID, domain, nonce, message, signature string) (*Result, error) {
+	return DefaultNamespaces.Verify(ctx, accountID, domain, nonce, message, signature)
+}
@@ -0,0 +1,84 @@
+package signature
+
+import (
+	"context"
+	"crypto/ed25519"
+	"strings"
+	"testing"
+)
+
+func TestParseAccountID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    AccountID
+		wantErr bool
+	}{
+		{name: "eip155", id: "eip155:1:" + wallet, want: AccountID{Namespace: "eip155", Reference: "1", Address: wallet}},
+		{name: "solana", id: "solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp:7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV", want: AccountID{Namespace: "solana", Reference: "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp", Address: "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV"}},
+		{name: "bip122", id: "bip122:000000000019d6689c085ae165831e93:128Lkh3S7CkDTBZ8W7BbpsN3YYizJMp8p6", want: AccountID{Namespace: "bip122", Reference: "000000000019d6689c085ae165831e93", Address: "128Lkh3S7CkDTBZ8W7BbpsN3YYizJMp8p6"}},
+		{name: "address", id: wallet, wantErr: true},
+		{name: "no reference", id: "eip155:" + wallet, wantErr: true},
+		{name: "invalid namespace", id: "EIP155:1:" + wallet, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAccountID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAccountID() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if *got != tt.want {
+				t.Errorf("ParseAccountID() got = %+v, want = %+v", *got, tt.want)
+			}
+			if got.String() != tt.id {
+				t.Errorf("String() got = %v, want = %v", got.String(), tt.id)
+			}
+		})
+	}
+}
+
+func TestNamespaceRegistry_Verify(t *testing.T) {
+	evmMessage, evmSignature, _ := Sign(nonce, signatureLifetime, privateKey)
+	_, key, _ := ed25519.GenerateKey(nil)
+	solanaAddress := base58Encode(key.Public().(ed25519.PublicKey))
+	solanaMessage, solanaSignature := signSolana(key, "5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp", signatureLifetime)
+	bitcoinMessage, bitcoinSignature := signBitcoin(bip322P2WPKHAddress, signatureLifetime, bip322P2WPKHWith(bitcoinKey(t)))
+
+	r := NewNamespaceRegistry()
+	r.Register("eip155", &Verifier{})
+	r.Register("solana", &SolanaVerifier{})
+	r.Register("bip122", &BitcoinVerifier{})
+
+	tests := []struct {
+		name      string
+		accountID string
+		message   string
+		signature string
+		wantErr   bool
+	}{
+		{name: "eip155", accountID: "eip155:1:" + wallet, message: evmMessage, signature: evmSignature, wantErr: false},
+		{name: "eip155 lowercase", accountID: "eip155:1:" + strings.ToLower(wallet), message: evmMessage, signature: evmSignature, wantErr: false},
+		{name: "solana", accountID: "solana:5eykt4UsFv8P8NJdTREpY1vzqKqZKvdp:" + solanaAddress, message: solanaMessage, signature: solanaSignature, wantErr: false},
+		{name: "bip122", accountID: "bip122:000000000019d6689c085ae165831e93:" + bip322P2WPKHAddress, message: bitcoinMessage, signature: bitcoinSignature, wantErr: false},
+		{name: "chain not match", accountID: "eip155:10:" + wallet, message: evmMessage, signature: evmSignature, wantErr: true},
+		{name: "address not match", accountID: "eip155:1:0x70997970C51812dc3A010C7d01b50e0d17dc79C8", message: evmMessage, signature: evmSignature, wantErr: true},
+		{name: "namespace not match", accountID: "solana:1:" + wallet, message: evmMessage, signature: evmSignature, wantErr: true},
+		{name: "namespace not registered", accountID: "polkadot:b0a8d493285c2df73290dfb7e61f870f:" + wallet, message: evmMessage, signature: evmSignature, wantErr: true},
+		{name: "not an account ID", accountID: wallet, message: evmMessage, signature: evmSignature, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Verify(context.Background(), tt.accountID, tDomain, nonce, tt.message, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Wallet != tt.accountID {
+				t.Errorf("Verify() wallet = %v, want = %v", got.Wallet, tt.accountID)
+			}
+		})
+	}
+}
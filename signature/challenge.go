@@ -0,0 +1,97 @@
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spruceid/siwe-go"
+)
+
+// ChallengeOptions are the fields of the SIWE message built by `NewChallenge`
+type ChallengeOptions struct {
+	Address        string    // the wallet which is asked to sign
+	Domain         string    // e.g. `app.seedao.xyz`
+	URI            string    // e.g. `https://app.seedao.xyz`
+	ChainID        int       // 1 if it's 0
+	Nonce          string    // generated by `GenerateNonce`
+	Statement      string    // shown to the user, omitted if empty
+	Resources      []string  // omitted if empty
+	RequestID      string    // omitted if empty
+	IssuedAt       time.Time // the current time if it's zero
+	NotBefore      time.Time // omitted if zero
+	ExpirationTime time.Time // omitted if zero
+}
+
+// ErrChallengeMismatch is returned by `CheckChallenge` when the message is not the issued challenge
+var ErrChallengeMismatch = errors.New("message not match challenge")
+
+// NewChallenge builds the EIP-4361 message which the wallet should sign, it returns the canonical string of the message.
+// Keep the returned string, and check the signed message by `CheckChallenge`
+func NewChallenge(opts ChallengeOptions) (string, error) {
+	if !common.IsHexAddress(opts.Address) {
+		return "", fmt.Errorf("invalid address %q", opts.Address)
+	}
+
+	chainID := opts.ChainID
+	if chainID == 0 {
+		chainID = 1
+	}
+	issuedAt := opts.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = time.Now()
+	}
+
+	options := map[string]interface{}{
+		"chainId":  chainID,
+		"issuedAt": issuedAt,
+	}
+	if opts.Statement != "" {
+		options["statement"] = opts.Statement
+	}
+	if opts.RequestID != "" {
+		options["requestId"] = opts.RequestID
+	}
+	if !opts.NotBefore.IsZero() {
+		options["notBefore"] = opts.NotBefore
+	}
+	if !opts.ExpirationTime.IsZero() {
+		options["expirationTime"] = opts.ExpirationTime
+	}
+	if len(opts.Resources) > 0 {
+		resources := make([]url.URL, len(opts.Resources))
+		for i, resource := range opts.Resources {
+			u, err := url.Parse(resource)
+			if err != nil {
+				return "", fmt.Errorf("invalid resource %q: %w", resource, err)
+			}
+			resources[i] = *u
+		}
+		options["resources"] = resources
+	}
+
+	m, err := siwe.InitMessage(opts.Domain, opts.Address, opts.URI, opts.Nonce, options)
+	if err != nil {
+		return "", err
+	}
+	return m.String(), nil
+}
+
+// CheckChallenge checks `message` is byte-for-byte the `challenge` issued by `NewChallenge`
+func CheckChallenge(challenge, message string) error {
+	if message == challenge {
+		return nil
+	}
+
+	// report the first different line
+	want, got := strings.Split(challenge, "\n"), strings.Split(message, "\n")
+	for i := 0; i < len(want) || i < len(got); i++ {
+		if i >= len(want) || i >= len(got) || want[i] != got[i] {
+			return fmt.Errorf("%w: line %d differs", ErrChallengeMismatch, i+1)
+		}
+	}
+	return ErrChallengeMismatch
+}
@@ -0,0 +1,113 @@
+package signature
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewChallenge(t *testing.T) {
+	issuedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		opts    ChallengeOptions
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "minimal",
+			opts: ChallengeOptions{Address: wallet, Domain: tDomain, URI: tUri, Nonce: nonce, IssuedAt: issuedAt},
+			want: "app.seedao.xyz wants you to sign in with your Ethereum account:\n" +
+				"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266\n\n\n" +
+				"URI: https://app.seedao.xyz\n" +
+				"Version: 1\n" +
+				"Chain ID: 1\n" +
+				"Nonce: oNCEHm5jzQU2WvuBB\n" +
+				"Issued At: 2024-06-01T12:00:00Z",
+			wantErr: false,
+		},
+		{
+			name: "full",
+			opts: ChallengeOptions{
+				Address:        wallet,
+				Domain:         tDomain,
+				URI:            tUri,
+				ChainID:        10,
+				Nonce:          nonce,
+				Statement:      tStatement,
+				Resources:      []string{"https://seedao.xyz/terms", "ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq"},
+				RequestID:      "request-1",
+				IssuedAt:       issuedAt,
+				NotBefore:      issuedAt,
+				ExpirationTime: issuedAt.Add(time.Minute),
+			},
+			want: "app.seedao.xyz wants you to sign in with your Ethereum account:\n" +
+				"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266\n\n" +
+				"Welcome to SeeDAO!\n\n" +
+				"URI: https://app.seedao.xyz\n" +
+				"Version: 1\n" +
+				"Chain ID: 10\n" +
+				"Nonce: oNCEHm5jzQU2WvuBB\n" +
+				"Issued At: 2024-06-01T12:00:00Z\n" +
+				"Expiration Time: 2024-06-01T12:01:00Z\n" +
+				"Not Before: 2024-06-01T12:00:00Z\n" +
+				"Request ID: request-1\n" +
+				"Resources:\n" +
+				"- https://seedao.xyz/terms\n" +
+				"- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq",
+			wantErr: false,
+		},
+		{
+			name:    "invalid address",
+			opts:    ChallengeOptions{Address: "0x1234", Domain: tDomain, URI: tUri, Nonce: nonce},
+			wantErr: true,
+		},
+		{
+			name:    "no nonce",
+			opts:    ChallengeOptions{Address: wallet, Domain: tDomain, URI: tUri},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewChallenge(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewChallenge() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NewChallenge() got = %q, want = %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckChallenge(t *testing.T) {
+	challenge, err := NewChallenge(ChallengeOptions{Address: wallet, Domain: tDomain, URI: tUri, Nonce: nonce, Statement: tStatement})
+	if err != nil {
+		t.Fatalf("NewChallenge() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		wantErr bool
+	}{
+		{name: "same", message: challenge, wantErr: false},
+		{name: "trailing newline", message: challenge + "\n", wantErr: true},
+		{name: "last byte changed", message: challenge[:len(challenge)-1] + "X", wantErr: true},
+		{name: "empty", message: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckChallenge(challenge, tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckChallenge() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrChallengeMismatch) {
+				t.Errorf("CheckChallenge() error = %v, want ErrChallengeMismatch", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package signature
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+)
+
+// CosmosVerifier verifies ADR-036 signatures of Cosmos wallets like Keplr, which are secp256k1 signatures over an amino sign doc
+type CosmosVerifier struct {
+	// Prefix is the bech32 prefix of accepted addresses, e.g. `cosmos` or `osmo`. Addresses of any prefix are accepted if it's empty
+	Prefix string
+	// Policy is what the message must satisfy, `DefaultMessagePolicy` is used if it's nil
+	Policy *MessagePolicy
+}
+
+// Verify verifies the Sign-In-With-Cosmos `message` is signed by `wallet`, the bech32 address of the wallet.
+// The signature is the JSON `StdSignature` returned by Keplr `signArbitrary`, which has the public key
+func (v *CosmosVerifier) Verify(ctx context.Context, wallet, domain, nonce, message, signature string) (*Result, error) {
+	m, err := ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if m.Blockchain != "Cosmos" {
+		return nil, fmt.Errorf("not a Sign-In-With-Cosmos message: %s account", m.Blockchain)
+	}
+	if err = checkFields(v.Policy, m, domain, nonce); err != nil {
+		return nil, err
+	}
+	if m.Address != wallet {
+		return nil, errors.New("signer not match")
+	}
+
+	prefix, _, err := ParseCosmosAddress(wallet)
+	if err != nil {
+		return nil, err
+	}
+	if v.Prefix != "" && prefix != v.Prefix {
+		return nil, fmt.Errorf("address prefix %s, expect %s", prefix, v.Prefix)
+	}
+
+	var sig StdSignature
+	if err = json.Unmarshal([]byte(signature), &sig); err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	publicKey, err := sig.publicKey()
+	if err != nil {
+		return nil, err
+	}
+	if CosmosAddress(prefix, publicKey) != wallet {
+		return nil, errors.New("signer not match")
+	}
+
+	doc, err := NewADR036SignDoc(wallet, []byte(message)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if err = verifySecp256k1(publicKey, doc, sig.Signature); err != nil {
+		return nil, err
+	}
+	return &Result{Wallet: wallet, SignerType: SignerEOA}, nil
+}
+
+// StdSignature is the amino JSON signature of Cosmos, the public key and the signature are base64
+type StdSignature struct {
+	PubKey struct {
+		Type  string `json:"type"`
+		Value []byte `json:"value"`
+	} `json:"pub_key"`
+	Signature []byte `json:"signature"`
+}
+
+func (s *StdSignature) publicKey() (*btcec.PublicKey, error) {
+	if s.PubKey.Type != "tendermint/PubKeySecp256k1" {
+		return nil, fmt.Errorf("unsupported public key type %s", s.PubKey.Type)
+	}
+	if len(s.PubKey.Value) != btcec.PubKeyBytesLenCompressed {
+		return nil, fmt.Errorf("invalid public key length %d", len(s.PubKey.Value))
+	}
+	publicKey, err := btcec.ParsePubKey(s.PubKey.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// verifySecp256k1 verifies the 64 bytes `r || s` signature over the SHA-256 of `msg`, a high `s` is rejected as Cosmos does
+func verifySecp256k1(publicKey *btcec.PublicKey, msg, sig []byte) error {
+	if len(sig) != 64 {
+		return fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	var r, s btcec.ModNScalar
+	if r.SetByteSlice(sig[:32]) || s.SetByteSlice(sig[32:]) || r.IsZero() || s.IsZero() {
+		return errors.New("invalid signature")
+	}
+	if s.IsOverHalfOrder() {
+		return errors.New("signature s is not low")
+	}
+	hash := sha256.Sum256(msg)
+	if !ecdsa.NewSignature(&r, &s).Verify(hash[:], publicKey) {
+		return errors.New("signer not match")
+	}
+	return nil
+}
+
+// ADR036SignDoc is the amino sign doc of ADR-036, its fields are ordered by the JSON names so that it encodes to the sorted JSON
+type ADR036SignDoc struct {
+	AccountNumber string `json:"account_number"`
+	ChainID       string `json:"chain_id"`
+	Fee           struct {
+		Amount []json.RawMessage `json:"amount"`
+		Gas    string            `json:"gas"`
+	} `json:"fee"`
+	Memo     string      `json:"memo"`
+	Msgs     []ADR036Msg `json:"msgs"`
+	Sequence string      `json:"sequence"`
+}
+
+// ADR036Msg is the `sign/MsgSignData` message of ADR-036
+type ADR036Msg struct {
+	Type  string `json:"type"`
+	Value struct {
+		Data   []byte `json:"data"`
+		Signer string `json:"signer"`
+	} `json:"value"`
+}
+
+// NewADR036SignDoc creates the sign doc of `data` signed by `signer`
+func NewADR036SignDoc(signer string, data []byte) *ADR036SignDoc {
+	msg := ADR036Msg{Type: "sign/MsgSignData"}
+	msg.Value.Data = data
+	msg.Value.Signer = signer
+	doc := &ADR036SignDoc{AccountNumber: "0", Sequence: "0", Msgs: []ADR036Msg{msg}}
+	doc.Fee.Amount = []json.RawMessage{}
+	doc.Fee.Gas = "0"
+	return doc
+}
+
+// parseADR036SignDoc parses an amino sign doc, it must be an ADR-036 one: no chain, no fee, no memo, and only a `sign/MsgSignData`
+func parseADR036SignDoc(b []byte) (*ADR036SignDoc, error) {
+	var doc ADR036SignDoc
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("invalid sign doc: %w", err)
+	}
+	if doc.AccountNumber != "0" || doc.Sequence != "0" || doc.ChainID != "" || doc.Memo != "" ||
+		len(doc.Fee.Amount) != 0 || doc.Fee.Gas != "0" {
+		return nil, errors.New("not an ADR-036 sign doc")
+	}
+	if len(doc.Msgs) != 1 || doc.Msgs[0].Type != "sign/MsgSignData" {
+		return nil, errors.New("ADR-036 sign doc must have one sign/MsgSignData")
+	}
+	return &doc, nil
+}
+
+// Signer returns the address which signs the doc
+func (d *ADR036SignDoc) Signer() string {
+	return d.Msgs[0].Value.Signer
+}
+
+// Data returns the signed data
+func (d *ADR036SignDoc) Data() []byte {
+	return d.Msgs[0].Value.Data
+}
+
+// Bytes returns the sorted JSON of the doc, which is what's signed
+func (d *ADR036SignDoc) Bytes() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// ParseCosmosAddress parses a bech32 Cosmos account address, it returns the prefix and the 20 bytes address
+func ParseCosmosAddress(address string) (string, []byte, error) {
+	prefix, data, variant, err := bech32Decode(address)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid Cosmos address: %w", err)
+	}
+	if variant != bech32Const {
+		return "", nil, errors.New("invalid Cosmos address: bech32m checksum")
+	}
+	b, err := convertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid Cosmos address: %w", err)
+	}
+	if len(b) != 20 {
+		return "", nil, fmt.Errorf("invalid Cosmos address: %d bytes", len(b))
+	}
+	return prefix, b, nil
+}
+
+// CosmosAddress returns the bech32 address of `publicKey` with `prefix`
+func CosmosAddress(prefix string, publicKey *btcec.PublicKey) string {
+	data, _ := convertBits(hash160(publicKey.SerializeCompressed()), 8, 5, true)
+	return bech32Encode(prefix, data, bech32Const)
+}
@@ -0,0 +1,171 @@
+package signature
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+)
+
+// signCosmos signs a Sign-In-With-Cosmos message of `address` by ADR-036, like Keplr `signArbitrary`
+func signCosmos(key *btcec.PrivateKey, address string, signatureLifetime time.Duration) (message, signature string) {
+	m := &Message{
+		Domain:         tDomain,
+		Blockchain:     "Cosmos",
+		Address:        address,
+		Statement:      tStatement,
+		URI:            tUri,
+		Version:        "1",
+		ChainID:        "cosmoshub-4",
+		Nonce:          nonce,
+		IssuedAt:       time.Now().UTC().Format(time.RFC3339),
+		ExpirationTime: time.Now().UTC().Add(signatureLifetime).Format(time.RFC3339),
+	}
+	message = m.String()
+	doc, _ := NewADR036SignDoc(address, []byte(message)).Bytes()
+	hash := sha256.Sum256(doc)
+	compact, _ := ecdsa.SignCompact(key, hash[:], true)
+
+	var sig StdSignature
+	sig.PubKey.Type = "tendermint/PubKeySecp256k1"
+	sig.PubKey.Value = key.PubKey().SerializeCompressed()
+	sig.Signature = compact[1:]
+	b, _ := json.Marshal(&sig)
+	return message, string(b)
+}
+
+func TestCosmosVerifier_Verify(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	otherKey, _ := btcec.NewPrivateKey()
+	address := CosmosAddress("cosmos", key.PubKey())
+	osmoAddress := CosmosAddress("osmo", key.PubKey())
+	message, signature := signCosmos(key, address, signatureLifetime)
+	_, otherSignature := signCosmos(otherKey, address, signatureLifetime)
+	osmoMessage, osmoSignature := signCosmos(key, osmoAddress, signatureLifetime)
+	expiredMessage, expiredSignature := signCosmos(key, address, -signatureLifetime)
+
+	// the same signature with a high `s`
+	var highS StdSignature
+	_ = json.Unmarshal([]byte(signature), &highS)
+	var s btcec.ModNScalar
+	s.SetByteSlice(highS.Signature[32:])
+	sBytes := s.Negate().Bytes()
+	copy(highS.Signature[32:], sBytes[:])
+	highSSignature, _ := json.Marshal(&highS)
+
+	tests := []struct {
+		name      string
+		prefix    string
+		wallet    string
+		message   string
+		signature string
+		wantErr   bool
+	}{
+		{name: "ok", wallet: address, message: message, signature: signature, wantErr: false},
+		{name: "prefix accepted", prefix: "cosmos", wallet: address, message: message, signature: signature, wantErr: false},
+		{name: "any prefix", wallet: osmoAddress, message: osmoMessage, signature: osmoSignature, wantErr: false},
+		{name: "prefix not accepted", prefix: "cosmos", wallet: osmoAddress, message: osmoMessage, signature: osmoSignature, wantErr: true},
+		{name: "signed by other", wallet: address, message: message, signature: otherSignature, wantErr: true},
+		{name: "wallet not match", wallet: osmoAddress, message: message, signature: signature, wantErr: true},
+		{name: "high s", wallet: address, message: message, signature: string(highSSignature), wantErr: true},
+		{name: "expired", wallet: address, message: expiredMessage, signature: expiredSignature, wantErr: true},
+		{name: "invalid signature", wallet: address, message: message, signature: "0x1234", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&CosmosVerifier{Prefix: tt.prefix}).Verify(context.Background(), tt.wallet, tDomain, nonce, tt.message, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Wallet != tt.wallet {
+				t.Errorf("Verify() wallet = %v, want = %v", got.Wallet, tt.wallet)
+			}
+		})
+	}
+}
+
+// TestCosmosVerifier_Vector verifies a fixed vector, which is not produced by this package: the sign doc is serialized by a port of
+// `makeADR36AminoSignDoc` and `serializeSignDoc` of CosmJS (what Keplr `signArbitrary` signs), and it's signed by OpenSSL
+func TestCosmosVerifier_Vector(t *testing.T) {
+	const (
+		address   = "cosmos1pvv0uqljv3r8p8pj4ma8wznxh2galz428ut0ck"
+		doc       = `{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[{"type":"sign/MsgSignData","value":{"data":"YXBwLnNlZWRhby54eXogd2FudHMgeW91IHRvIHNpZ24gaW4gd2l0aCB5b3VyIENvc21vcyBhY2NvdW50Ogpjb3Ntb3MxcHZ2MHVxbGp2M3I4cDhwajRtYTh3em54aDJnYWx6NDI4dXQwY2sKClNpZ24gaW4gdG8gU2VlREFPICYgYWNjZXB0IHRoZSA8VGVybXM+CgpVUkk6IGh0dHBzOi8vYXBwLnNlZWRhby54eXoKVmVyc2lvbjogMQpDaGFpbiBJRDogY29zbW9zaHViLTQKTm9uY2U6IG9OQ0VIbTVqelFVMld2dUJCCklzc3VlZCBBdDogMjAyNC0wNi0wMVQxMjowMDowMFoKRXhwaXJhdGlvbiBUaW1lOiAyMDI0LTA2LTAxVDEyOjEwOjAwWg==","signer":"cosmos1pvv0uqljv3r8p8pj4ma8wznxh2galz428ut0ck"}}],"sequence":"0"}`
+		signature = `{"pub_key":{"type":"tendermint/PubKeySecp256k1","value":"AyNHfy6iajaHx2AFQi3bNBTKycHb3nU11FqrJNRNCxwZ"},"signature":"qSxmlE1I8aPoIx+HC1GebQKq7S2L65ZneTM8gkRGqWkPrRuy/T3UbCMJqcrUbwmbjSE0Yb4X/lj0Nxxrjf0Cpg=="}`
+	)
+	message := "app.seedao.xyz wants you to sign in with your Cosmos account:\n" +
+		"cosmos1pvv0uqljv3r8p8pj4ma8wznxh2galz428ut0ck\n\n" +
+		"Sign in to SeeDAO & accept the <Terms>\n\n" +
+		"URI: https://app.seedao.xyz\n" +
+		"Version: 1\n" +
+		"Chain ID: cosmoshub-4\n" +
+		"Nonce: oNCEHm5jzQU2WvuBB\n" +
+		"Issued At: 2024-06-01T12:00:00Z\n" +
+		"Expiration Time: 2024-06-01T12:10:00Z"
+
+	got, err := NewADR036SignDoc(address, []byte(message)).Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if string(got) != doc {
+		t.Errorf("Bytes() got = %s, want = %s", got, doc)
+	}
+
+	policy := &MessagePolicy{Now: func() time.Time { return time.Date(2024, 6, 1, 12, 5, 0, 0, time.UTC) }}
+	result, err := (&CosmosVerifier{Prefix: "cosmos", Policy: policy}).Verify(context.Background(), address, tDomain, nonce, message, signature)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Wallet != address {
+		t.Errorf("Verify() wallet = %v, want = %v", result.Wallet, address)
+	}
+}
+
+func TestADR036SignDoc(t *testing.T) {
+	const want = `{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[{"type":"sign/MsgSignData","value":{"data":"aGVsbG8=","signer":"cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs6"}}],"sequence":"0"}`
+
+	doc, err := NewADR036SignDoc("cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs6", []byte("hello")).Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if string(doc) != want {
+		t.Errorf("Bytes() got = %s, want = %s", doc, want)
+	}
+
+	parsed, err := parseADR036SignDoc([]byte(want))
+	if err != nil {
+		t.Fatalf("parseADR036SignDoc() error = %v", err)
+	}
+	if parsed.Signer() != "cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs6" || string(parsed.Data()) != "hello" {
+		t.Errorf("parseADR036SignDoc() got = %+v", parsed)
+	}
+
+	for _, doc := range []string{
+		`{"account_number":"1","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[{"type":"sign/MsgSignData","value":{"data":"aGVsbG8=","signer":"cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs6"}}],"sequence":"0"}`,
+		`{"account_number":"0","chain_id":"cosmoshub-4","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[{"type":"sign/MsgSignData","value":{"data":"aGVsbG8=","signer":"cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs6"}}],"sequence":"0"}`,
+		`{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[{"type":"cosmos-sdk/MsgSend","value":{}}],"sequence":"0"}`,
+	} {
+		if _, err = parseADR036SignDoc([]byte(doc)); err == nil {
+			t.Errorf("parseADR036SignDoc(%s) error = nil", doc)
+		}
+	}
+}
+
+func TestParseCosmosAddress(t *testing.T) {
+	prefix, b, err := ParseCosmosAddress("cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs6")
+	if err != nil {
+		t.Fatalf("ParseCosmosAddress() error = %v", err)
+	}
+	if prefix != "cosmos" || len(b) != 20 {
+		t.Errorf("ParseCosmosAddress() got = %v, %x", prefix, b)
+	}
+	for _, address := range []string{"cosmos1pkptre7fdkl6gfrzlesjjvhxhlc3r4gmmk8rs7", bip322P2TRAddress, wallet} {
+		if _, _, err = ParseCosmosAddress(address); err == nil {
+			t.Errorf("ParseCosmosAddress(%s) error = nil", address)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ContractCaller reads the chain, it's satisfied by `*ethclient.Client` and go-ethereum's simulated backend
+type ContractCaller interface {
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// eip1271MagicValue is returned by `isValidSignature` when the signature is valid,
+// it's `bytes4(keccak256("isValidSignature(bytes32,bytes)"))`
+var eip1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+
+var eip1271ABI, _ = abi.JSON(strings.NewReader(`[{
+	"type": "function",
+	"name": "isValidSignature",
+	"stateMutability": "view",
+	"inputs": [{"name": "hash", "type": "bytes32"}, {"name": "signature", "type": "bytes"}],
+	"outputs": [{"name": "magicValue", "type": "bytes4"}]
+}]`))
+
+// isValidSignature calls EIP-1271 `isValidSignature(hash, sig)` of `contract`
+func isValidSignature(ctx context.Context, caller ContractCaller, contract common.Address, hash common.Hash, sig []byte) error {
+	data, err := eip1271ABI.Pack("isValidSignature", hash, sig)
+	if err != nil {
+		return err
+	}
+
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("call isValidSignature: %w", err)
+	}
+	if len(out) < 4 || !bytes.Equal(out[:4], eip1271MagicValue) {
+		return errors.New("signer not match")
+	}
+	return nil
+}
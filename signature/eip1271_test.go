@@ -0,0 +1,96 @@
+package signature
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	ownerKey         = "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"
+	contractWallet   = "0x0000000000000000000000000000000000001271"
+	contractGasLimit = 10_000_000
+)
+
+// eip1271WalletCode is the runtime code of a minimal EIP-1271 wallet,
+// `isValidSignature(hash, sig)` returns the magic value when `sig` is a 65 bytes signature of `hash` signed by `owner`
+func eip1271WalletCode(owner common.Address) []byte {
+	code := []byte{
+		0x60, 0x04, 0x35, 0x60, 0x00, 0x52, // mstore(0x00, hash)
+		0x60, 0xa4, 0x35, 0x60, 0x00, 0x1a, 0x60, 0x20, 0x52, // mstore(0x20, v)
+		0x60, 0x64, 0x35, 0x60, 0x40, 0x52, // mstore(0x40, r)
+		0x60, 0x84, 0x35, 0x60, 0x60, 0x52, // mstore(0x60, s)
+		0x60, 0x20, 0x60, 0x00, 0x60, 0x80, 0x60, 0x00, 0x60, 0x01, 0x5a, 0xfa, 0x50, // staticcall(gas, ecrecover, 0x00, 0x80, 0x00, 0x20)
+		0x60, 0x00, 0x51, 0x73, // mload(0x00) == owner
+	}
+	code = append(code, owner.Bytes()...)
+	code = append(code,
+		0x14, 0x60, 0x54, 0x57, // jumpi(valid, eq)
+		0x63, 0xff, 0xff, 0xff, 0xff, 0x60, 0xe0, 0x1b, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3, // return(0xffffffff)
+		0x5b, 0x63, 0x16, 0x26, 0xba, 0x7e, 0x60, 0xe0, 0x1b, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3, // valid: return(magic value)
+	)
+	return code
+}
+
+func newSimulatedBackend(t *testing.T, alloc core.GenesisAlloc) *backends.SimulatedBackend {
+	t.Helper()
+	backend := backends.NewSimulatedBackend(alloc, contractGasLimit)
+	t.Cleanup(func() { _ = backend.Close() })
+	return backend
+}
+
+func mustKey(t *testing.T, hexKey string) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := crypto.HexToECDSA(hexKey)
+	if err != nil {
+		t.Fatalf("HexToECDSA() error = %v", err)
+	}
+	return key
+}
+
+func TestVerifier_Verify_EIP1271(t *testing.T) {
+	owner := mustKey(t, ownerKey)
+	other := mustKey(t, privateKey)
+	backend := newSimulatedBackend(t, core.GenesisAlloc{
+		common.HexToAddress(contractWallet): {Code: eip1271WalletCode(crypto.PubkeyToAddress(owner.PublicKey)), Balance: big.NewInt(0)},
+	})
+
+	tests := []struct {
+		name           string
+		caller         ContractCaller
+		address        string
+		key            *ecdsa.PrivateKey
+		wantSignerType SignerType
+		wantErr        bool
+	}{
+		{name: "eoa without caller", caller: nil, address: wallet, key: other, wantSignerType: SignerEOA, wantErr: false},
+		{name: "eoa with caller", caller: backend, address: wallet, key: other, wantSignerType: SignerEOA, wantErr: false},
+		{name: "contract signed by owner", caller: backend, address: contractWallet, key: owner, wantSignerType: SignerContract, wantErr: false},
+		{name: "contract signed by other", caller: backend, address: contractWallet, key: other, wantErr: true},
+		{name: "contract without caller", caller: nil, address: contractWallet, key: owner, wantErr: true},
+		{name: "eoa signed by other", caller: backend, address: crypto.PubkeyToAddress(owner.PublicKey).Hex(), key: other, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, signature, err := sign(tt.address, nonce, signatureLifetime, tt.key)
+			if err != nil {
+				t.Fatalf("sign() error = %v", err)
+			}
+
+			got, err := (&Verifier{Caller: tt.caller}).Verify(context.Background(), tt.address, tDomain, nonce, message, signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.SignerType != tt.wantSignerType {
+				t.Errorf("Verify() signerType = %v, want = %v", got.SignerType, tt.wantSignerType)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// eip6492MagicSuffix is appended to the signature of a counterfactual (not deployed yet) contract wallet,
+// the signature is `abi.encode(factory, factoryCalldata, signature) ++ magic`
+var eip6492MagicSuffix = common.FromHex("0x6492649264926492649264926492649264926492649264926492649264926492")
+
+var eip6492Arguments = abi.Arguments{
+	{Type: mustNewType("address")}, // factory
+	{Type: mustNewType("bytes")},   // factory calldata
+	{Type: mustNewType("bytes")},   // signature
+}
+
+var universalValidatorArguments = abi.Arguments{
+	{Type: mustNewType("address")}, // signer
+	{Type: mustNewType("bytes32")}, // hash
+	{Type: mustNewType("bytes")},   // signature
+}
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// eip6492Signature is an unwrapped EIP-6492 signature
+type eip6492Signature struct {
+	Factory         common.Address
+	FactoryCalldata []byte
+	Signature       []byte // the signature checked by `isValidSignature` after the wallet is deployed
+}
+
+// isEIP6492Signature tells whether `sig` is wrapped by EIP-6492
+func isEIP6492Signature(sig []byte) bool {
+	return len(sig) >= len(eip6492MagicSuffix) && bytes.HasSuffix(sig, eip6492MagicSuffix)
+}
+
+// parseEIP6492Signature unwraps the factory, the factory calldata and the signature from an EIP-6492 signature
+func parseEIP6492Signature(sig []byte) (*eip6492Signature, error) {
+	if !isEIP6492Signature(sig) {
+		return nil, errors.New("not an EIP-6492 signature")
+	}
+
+	values, err := eip6492Arguments.Unpack(sig[:len(sig)-len(eip6492MagicSuffix)])
+	if err != nil {
+		return nil, fmt.Errorf("invalid EIP-6492 signature: %w", err)
+	}
+	return &eip6492Signature{
+		Factory:         values[0].(common.Address),
+		FactoryCalldata: values[1].([]byte),
+		Signature:       values[2].([]byte),
+	}, nil
+}
+
+// universalValidatorCode is the creation code of a minimal universal validator, it's executed by `eth_call` without deploying.
+// The constructor arguments are `abi.encode(signer, hash, signature)`, and `signature` must be wrapped by EIP-6492.
+// When `signer` has no code, the factory is called with the factory calldata to deploy it,
+// then it returns 0x01 if `signer.isValidSignature(hash, unwrapped signature)` returns the EIP-1271 magic value, otherwise 0x00
+var universalValidatorCode = []byte{
+	0x61, 0x00, 0x79, 0x80, 0x38, 0x03, 0x90, 0x60, 0x00, 0x39, // codecopy(0x00, 0x79, codesize - 0x79), 0x79 is the length of this code
+	0x60, 0x00, 0x51, 0x3b, 0x60, 0x2a, 0x57, // jumpi(deployed, extcodesize(signer))
+	0x60, 0xa0, 0x51, 0x60, 0x80, 0x01, // p := 0x80 + offset of factory calldata
+	0x60, 0x00, 0x60, 0x00, 0x82, 0x51, 0x83, 0x60, 0x20, 0x01, 0x60, 0x00, 0x60, 0x80, 0x51, 0x5a, 0xf1, 0x50, 0x50, // pop(call(gas, factory, 0, p + 0x20, mload(p), 0, 0))
+	0x5b, 0x60, 0xc0, 0x51, 0x60, 0x80, 0x01, // deployed: p := 0x80 + offset of signature
+	0x60, 0x20, 0x51, 0x63, 0x16, 0x26, 0xba, 0x7e, 0x60, 0xe0, 0x1b, 0x60, 0x44, 0x83, 0x03, 0x52, // mstore(p - 0x44, selector of isValidSignature)
+	0x60, 0x40, 0x82, 0x03, 0x52, // mstore(p - 0x40, hash)
+	0x60, 0x40, 0x60, 0x20, 0x82, 0x03, 0x52, // mstore(p - 0x20, 0x40)
+	0x60, 0x20, 0x60, 0x00, 0x82, 0x51, 0x60, 0x64, 0x01, 0x60, 0x44, 0x84, 0x03, 0x60, 0x00, 0x51, // staticcall(gas, signer, p - 0x44, mload(p) + 0x64, 0x00, 0x20)
+	0x60, 0x00, 0x60, 0x00, 0x52, 0x5a, 0xfa, // (memory 0x00 is cleared before the call)
+	0x60, 0x00, 0x51, 0x63, 0x16, 0x26, 0xba, 0x7e, 0x60, 0xe0, 0x1b, 0x14, 0x16, 0x60, 0x00, 0x53, // mstore8(0x00, and(success, eq(mload(0x00), magic value)))
+	0x60, 0x01, 0x60, 0x00, 0xf3, // return(0x00, 0x01)
+}
+
+// isValidSignatureOffchain verifies an EIP-6492 signature of a counterfactual wallet,
+// the universal validator is executed by `eth_call`, so the wallet is deployed only in the call
+func isValidSignatureOffchain(ctx context.Context, caller ContractCaller, signer common.Address, hash common.Hash, sig []byte) error {
+	args, err := universalValidatorArguments.Pack(signer, hash, sig)
+	if err != nil {
+		return err
+	}
+	data := append(append([]byte{}, universalValidatorCode...), args...)
+
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("call universal validator: %w", err)
+	}
+	if len(out) != 1 || out[0] != 0x01 {
+		return errors.New("signer not match")
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package signature
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const factory = "0x0000000000000000000000000000000000006492"
+
+// create2FactoryCode is the runtime code of a minimal factory, it deploys the calldata as creation code by CREATE2 with salt 0
+var create2FactoryCode = []byte{
+	0x36, 0x60, 0x00, 0x60, 0x00, 0x37, // calldatacopy(0x00, 0x00, calldatasize)
+	0x60, 0x00, 0x36, 0x60, 0x00, 0x60, 0x00, 0xf5, 0x00, // create2(0, 0x00, calldatasize, 0)
+}
+
+// creationCode wraps `runtime` to creation code
+func creationCode(runtime []byte) []byte {
+	return append([]byte{0x60, byte(len(runtime)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}, runtime...)
+}
+
+// wrapEIP6492 wraps `sig` as EIP-6492 does
+func wrapEIP6492(t *testing.T, factory common.Address, factoryCalldata []byte, sig string) string {
+	t.Helper()
+	packed, err := eip6492Arguments.Pack(factory, factoryCalldata, common.FromHex(sig))
+	if err != nil {
+		t.Fatalf("Pack() error = %v", err)
+	}
+	return hexutil.Encode(append(packed, eip6492MagicSuffix...))
+}
+
+func TestVerifier_Verify_EIP6492(t *testing.T) {
+	owner := mustKey(t, ownerKey)
+	other := mustKey(t, privateKey)
+	walletCreationCode := creationCode(eip1271WalletCode(crypto.PubkeyToAddress(owner.PublicKey)))
+	counterfactualWallet := crypto.CreateAddress2(common.HexToAddress(factory), [32]byte{}, crypto.Keccak256(walletCreationCode)).Hex()
+	backend := newSimulatedBackend(t, core.GenesisAlloc{
+		common.HexToAddress(factory):        {Code: create2FactoryCode, Balance: big.NewInt(0)},
+		common.HexToAddress(contractWallet): {Code: eip1271WalletCode(crypto.PubkeyToAddress(owner.PublicKey)), Balance: big.NewInt(0)},
+	})
+
+	tests := []struct {
+		name            string
+		caller          ContractCaller
+		address         string
+		key             *ecdsa.PrivateKey
+		factoryCalldata []byte
+		wantErr         bool
+	}{
+		{name: "counterfactual signed by owner", caller: backend, address: counterfactualWallet, key: owner, factoryCalldata: walletCreationCode, wantErr: false},
+		{name: "counterfactual signed by other", caller: backend, address: counterfactualWallet, key: other, factoryCalldata: walletCreationCode, wantErr: true},
+		{name: "counterfactual deployed by wrong calldata", caller: backend, address: counterfactualWallet, key: owner, factoryCalldata: []byte{0x00}, wantErr: true},
+		{name: "counterfactual without caller", caller: nil, address: counterfactualWallet, key: owner, factoryCalldata: walletCreationCode, wantErr: true},
+		{name: "deployed signed by owner", caller: backend, address: contractWallet, key: owner, factoryCalldata: walletCreationCode, wantErr: false},
+		{name: "deployed signed by other", caller: backend, address: contractWallet, key: other, factoryCalldata: walletCreationCode, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, signature, err := sign(tt.address, nonce, signatureLifetime, tt.key)
+			if err != nil {
+				t.Fatalf("sign() error = %v", err)
+			}
+			signature = wrapEIP6492(t, common.HexToAddress(factory), tt.factoryCalldata, signature)
+
+			got, err := (&Verifier{Caller: tt.caller}).Verify(context.Background(), tt.address, tDomain, nonce, message, signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.SignerType != SignerContract {
+				t.Errorf("Verify() signerType = %v, want = %v", got.SignerType, SignerContract)
+			}
+		})
+	}
+
+	// the wallet is deployed only in `eth_call`
+	code, err := backend.CodeAt(context.Background(), common.HexToAddress(counterfactualWallet), nil)
+	if err != nil || len(code) != 0 {
+		t.Errorf("CodeAt() code = %x, error = %v", code, err)
+	}
+}
+
+func Test_parseEIP6492Signature(t *testing.T) {
+	tests := []struct {
+		name    string
+		sig     string
+		wantErr bool
+	}{
+		{name: "ok", sig: wrapEIP6492(t, common.HexToAddress(factory), []byte{0x01, 0x02}, "0x0304"), wantErr: false},
+		{name: "no magic suffix", sig: "0x0304", wantErr: true},
+		{name: "malformed", sig: hexutil.Encode(append([]byte{0x01}, eip6492MagicSuffix...)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEIP6492Signature(common.FromHex(tt.sig))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseEIP6492Signature() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.Factory.Hex() != factory || hexutil.Encode(got.FactoryCalldata) != "0x0102" || hexutil.Encode(got.Signature) != "0x0304") {
+				t.Errorf("parseEIP6492Signature() got = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// JoyIDAssertion is the signature of JoyID, it's a WebAuthn assertion signed by a passkey (P-256).
+// The `signature` of the SIWE message is the JSON of it
+type JoyIDAssertion struct {
+	PublicKey         string `json:"pubkey"`            // hex of the uncompressed public key, `04 || x || y` or `x || y`
+	AuthenticatorData string `json:"authenticatorData"` // base64url
+	ClientDataJSON    string `json:"clientDataJSON"`    // base64url
+	Signature         string `json:"signature"`         // base64url of the ASN.1 DER signature, or the raw `r || s`
+}
+
+// clientData is the part of `clientDataJSON` which is checked
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// WebAuthnChallenge returns the challenge which the passkey must sign for the SIWE `message`,
+// it's base64url (without padding) of sha256 of the message
+func WebAuthnChallenge(message string) string {
+	digest := sha256.Sum256([]byte(message))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// PasskeyAddress derives the wallet address from a passkey as Ethereum does, it's the last 20 bytes of `keccak256(x || y)`.
+// It's NOT confirmed to be how JoyID derives addresses, it's only tested by assertions generated by this package, not by real JoyID ones.
+// If JoyID wallets fail with "passkey not match wallet", set `JoyIDVerifier.Address` to the derivation of JoyID
+func PasskeyAddress(publicKey *ecdsa.PublicKey) common.Address {
+	x := publicKey.X.FillBytes(make([]byte, 32))
+	y := publicKey.Y.FillBytes(make([]byte, 32))
+	return common.BytesToAddress(crypto.Keccak256(x, y)[12:])
+}
+
+// the relying party ID and the origins of JoyID passkeys, they are NOT confirmed by real JoyID assertions either
+var (
+	JoyIDRPID    = "joy.id"
+	JoyIDOrigins = []string{"https://app.joy.id"}
+)
+
+// JoyIDVerifier verifies SIWE signatures of JoyID, which are WebAuthn assertions
+type JoyIDVerifier struct {
+	// RPID is the relying party ID of the passkey, `rpIdHash` of the authenticator data is not checked if it's empty
+	RPID string
+	// Origins are the accepted origins of `clientDataJSON`, e.g. `JoyIDOrigins`, the origin is not checked if it's empty
+	Origins []string
+	// Address derives the wallet address from the passkey, `PasskeyAddress` is used if it's nil, which is unverified against JoyID
+	Address func(publicKey *ecdsa.PublicKey) common.Address
+	// Policy is what the SIWE message must satisfy, `DefaultMessagePolicy` is used if it's nil
+	Policy *MessagePolicy
+}
+
+// Verify verifies the SIWE `message` is signed by the passkey of `wallet`,
+// the challenge of the assertion must be `WebAuthnChallenge(message)`
+func (v *JoyIDVerifier) Verify(ctx context.Context, wallet, domain, nonce, message, signature string) (*Result, error) {
+	m, err := checkMessage(v.Policy, wallet, domain, nonce, message)
+	if err != nil {
+		return nil, err
+	}
+
+	var assertion JoyIDAssertion
+	if err = json.Unmarshal([]byte(signature), &assertion); err != nil {
+		return nil, fmt.Errorf("invalid JoyID signature: %w", err)
+	}
+
+	// the passkey must be bound to the wallet
+	publicKey, err := parseP256PublicKey(assertion.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	address := PasskeyAddress
+	if v.Address != nil {
+		address = v.Address
+	}
+	if address(publicKey) != m.GetAddress() {
+		return nil, errors.New("passkey not match wallet")
+	}
+
+	// the assertion must be requested for this message
+	clientDataJSON, err := decodeBase64URL(assertion.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clientDataJSON: %w", err)
+	}
+	var cd clientData
+	if err = json.Unmarshal(clientDataJSON, &cd); err != nil {
+		return nil, fmt.Errorf("invalid clientDataJSON: %w", err)
+	}
+	if cd.Type != "webauthn.get" {
+		return nil, fmt.Errorf("invalid clientDataJSON type %q", cd.Type)
+	}
+	if cd.Challenge != WebAuthnChallenge(message) {
+		return nil, errors.New("challenge not match message")
+	}
+	if len(v.Origins) > 0 && !containsString(v.Origins, cd.Origin) {
+		return nil, fmt.Errorf("origin %q not accepted", cd.Origin)
+	}
+
+	authenticatorData, err := decodeBase64URL(assertion.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authenticatorData: %w", err)
+	}
+	if len(authenticatorData) < 37 { // rpIdHash(32) + flags(1) + signCount(4)
+		return nil, errors.New("invalid authenticatorData: too short")
+	}
+	if v.RPID != "" {
+		rpIDHash := sha256.Sum256([]byte(v.RPID))
+		if !bytes.Equal(authenticatorData[:32], rpIDHash[:]) {
+			return nil, errors.New("rpIdHash not match")
+		}
+	}
+	if authenticatorData[32]&0x01 == 0 {
+		return nil, errors.New("user not present")
+	}
+
+	// the signature is over `authenticatorData || sha256(clientDataJSON)`
+	sig, err := decodeBase64URL(assertion.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authenticatorData...), clientDataHash[:]...))
+	if !verifyP256(publicKey, digest[:], sig) {
+		return nil, errors.New("signer not match")
+	}
+
+	return &Result{Wallet: wallet, SignerType: SignerPasskey}, nil
+}
+
+// parseP256PublicKey parses the hex of an uncompressed P-256 public key
+func parseP256PublicKey(s string) (*ecdsa.PublicKey, error) {
+	b := common.FromHex(s)
+	if len(b) == 64 {
+		b = append([]byte{0x04}, b...)
+	}
+	// `ecdh` checks the point is on the curve
+	if _, err := ecdh.P256().NewPublicKey(b); err != nil {
+		return nil, fmt.Errorf("invalid passkey: %w", err)
+	}
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(b[1:33]),
+		Y:     new(big.Int).SetBytes(b[33:65]),
+	}, nil
+}
+
+// verifyP256 verifies an ASN.1 DER signature, or a raw `r || s` signature
+func verifyP256(publicKey *ecdsa.PublicKey, digest, sig []byte) bool {
+	if len(sig) == 64 {
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(publicKey, digest, r, s)
+	}
+	return ecdsa.VerifyASN1(publicKey, digest, sig)
+}
+
+func containsString(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
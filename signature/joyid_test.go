@@ -0,0 +1,88 @@
+package signature
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const joyIDRPID = "joy.id"
+
+// assertJoyID signs `challenge` by `key` as a passkey of JoyID does, and returns the JSON of the assertion
+func assertJoyID(t *testing.T, key *ecdsa.PrivateKey, rpID, typ, challenge string) string {
+	t.Helper()
+	return assertPasskey(t, key, rpID, "https://app.joy.id", typ, challenge)
+}
+
+// assertPasskey signs `challenge` by `key` as a passkey used on `origin` does, and returns the JSON of the assertion
+func assertPasskey(t *testing.T, key *ecdsa.PrivateKey, rpID, origin, typ, challenge string) string {
+	t.Helper()
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authenticatorData := append(rpIDHash[:], 0x05, 0x00, 0x00, 0x00, 0x01) // user present and verified, signCount 1
+	clientDataJSON := []byte(fmt.Sprintf(`{"type":%q,"challenge":%q,"origin":%q}`, typ, challenge, origin))
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authenticatorData...), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("SignASN1() error = %v", err)
+	}
+
+	assertion, _ := json.Marshal(&JoyIDAssertion{
+		PublicKey:         hexutil.Encode(elliptic.Marshal(elliptic.P256(), key.X, key.Y)),
+		AuthenticatorData: base64.RawURLEncoding.EncodeToString(authenticatorData),
+		ClientDataJSON:    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+		Signature:         base64.RawURLEncoding.EncodeToString(sig),
+	})
+	return string(assertion)
+}
+
+func TestJoyIDVerifier_Verify(t *testing.T) {
+	passkey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	address := PasskeyAddress(&passkey.PublicKey).Hex()
+	message, _, err := sign(address, nonce, signatureLifetime, mustKey(t, privateKey))
+	if err != nil {
+		t.Fatalf("sign() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		verifier  *JoyIDVerifier
+		wallet    string
+		signature string
+		wantErr   bool
+	}{
+		{name: "ok", verifier: &JoyIDVerifier{RPID: joyIDRPID}, wallet: address, signature: assertJoyID(t, passkey, joyIDRPID, "webauthn.get", WebAuthnChallenge(message)), wantErr: false},
+		{name: "rpId not checked", verifier: &JoyIDVerifier{}, wallet: address, signature: assertJoyID(t, passkey, "example.com", "webauthn.get", WebAuthnChallenge(message)), wantErr: false},
+		{name: "rpId not match", verifier: &JoyIDVerifier{RPID: joyIDRPID}, wallet: address, signature: assertJoyID(t, passkey, "example.com", "webauthn.get", WebAuthnChallenge(message)), wantErr: true},
+		{name: "challenge not match", verifier: &JoyIDVerifier{}, wallet: address, signature: assertJoyID(t, passkey, joyIDRPID, "webauthn.get", WebAuthnChallenge("another message")), wantErr: true},
+		{name: "not an assertion", verifier: &JoyIDVerifier{}, wallet: address, signature: assertJoyID(t, passkey, joyIDRPID, "webauthn.create", WebAuthnChallenge(message)), wantErr: true},
+		{name: "passkey of another wallet", verifier: &JoyIDVerifier{}, wallet: address, signature: assertJoyID(t, other, joyIDRPID, "webauthn.get", WebAuthnChallenge(message)), wantErr: true},
+		{name: "wallet not match", verifier: &JoyIDVerifier{}, wallet: wallet, signature: assertJoyID(t, passkey, joyIDRPID, "webauthn.get", WebAuthnChallenge(message)), wantErr: true},
+		{name: "JoyID", verifier: &JoyIDVerifier{RPID: JoyIDRPID, Origins: JoyIDOrigins}, wallet: address, signature: assertJoyID(t, passkey, joyIDRPID, "webauthn.get", WebAuthnChallenge(message)), wantErr: false},
+		{name: "origin not accepted", verifier: &JoyIDVerifier{RPID: JoyIDRPID, Origins: JoyIDOrigins}, wallet: address, signature: assertPasskey(t, passkey, joyIDRPID, "https://evil.example", "webauthn.get", WebAuthnChallenge(message)), wantErr: true},
+		{name: "rpIdHash not accepted", verifier: &JoyIDVerifier{RPID: JoyIDRPID, Origins: JoyIDOrigins}, wallet: address, signature: assertPasskey(t, passkey, "evil.example", "https://app.joy.id", "webauthn.get", WebAuthnChallenge(message)), wantErr: true},
+		{name: "not json", verifier: &JoyIDVerifier{}, wallet: address, signature: "0x1234", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Verify(context.Background(), tt.wallet, tDomain, nonce, message, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.SignerType != SignerPasskey {
+				t.Errorf("Verify() signerType = %v, want = %v", got.SignerType, SignerPasskey)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Message is a Sign-In-With-X message, it's the format of EIP-4361 shared by other blockchains, e.g. Sign-In-With-Solana
+type Message struct {
+	Domain         string
+	Blockchain     string // the name in "sign in with your ... account", e.g. "Ethereum" or "Solana"
+	Address        string
+	Statement      string // empty if there is no statement
+	URI            string
+	Version        string
+	ChainID        string
+	Nonce          string
+	IssuedAt       string // empty if there is no issued-at time, it's optional only in Sign-In-With-Solana
+	ExpirationTime string // empty if there is no expiration time
+	NotBefore      string // empty if there is no not-before time
+	RequestID      string // empty if there is no request ID
+	Resources      []string
+}
+
+var messageHeader = regexp.MustCompile(`^(\S+) wants you to sign in with your (.+) account:$`)
+
+// ParseMessage parses a Sign-In-With-X message, the message is not verified
+func ParseMessage(text string) (*Message, error) {
+	lines := strings.Split(text, "\n")
+	if len(lines) < 4 {
+		return nil, errors.New("invalid message: too short")
+	}
+
+	header := messageHeader.FindStringSubmatch(lines[0])
+	if header == nil {
+		return nil, errors.New("invalid message: invalid header")
+	}
+	m := &Message{Domain: header[1], Blockchain: header[2], Address: lines[1]}
+	if m.Address == "" || lines[2] != "" {
+		return nil, errors.New("invalid message: invalid address")
+	}
+
+	// the statement is optional, it's surrounded by empty lines
+	i := 4
+	if lines[3] != "" {
+		if len(lines) < 5 || lines[4] != "" {
+			return nil, errors.New("invalid message: invalid statement")
+		}
+		m.Statement = lines[3]
+		i = 5
+	}
+
+	field := func(prefix string, required bool) (string, error) {
+		if i < len(lines) && strings.HasPrefix(lines[i], prefix) {
+			i++
+			return strings.TrimPrefix(lines[i-1], prefix), nil
+		}
+		if required {
+			return "", fmt.Errorf("invalid message: missing %q", strings.TrimSuffix(prefix, ": "))
+		}
+		return "", nil
+	}
+	for _, f := range []struct {
+		prefix   string
+		required bool
+		value    *string
+	}{
+		{"URI: ", true, &m.URI},
+		{"Version: ", true, &m.Version},
+		{"Chain ID: ", true, &m.ChainID},
+		{"Nonce: ", true, &m.Nonce},
+		{"Issued At: ", m.Blockchain != "Solana", &m.IssuedAt},
+		{"Expiration Time: ", false, &m.ExpirationTime},
+		{"Not Before: ", false, &m.NotBefore},
+		{"Request ID: ", false, &m.RequestID},
+	} {
+		value, err := field(f.prefix, f.required)
+		if err != nil {
+			return nil, err
+		}
+		*f.value = value
+	}
+
+	if i < len(lines) && lines[i] == "Resources:" {
+		for i++; i < len(lines) && strings.HasPrefix(lines[i], "- "); i++ {
+			m.Resources = append(m.Resources, strings.TrimPrefix(lines[i], "- "))
+		}
+	}
+	if i != len(lines) {
+		return nil, fmt.Errorf("invalid message: unexpected line %q", lines[i])
+	}
+	return m, nil
+}
+
+// String returns the canonical text of the message, which is signed by the wallet
+func (m *Message) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s wants you to sign in with your %s account:\n%s\n\n", m.Domain, m.Blockchain, m.Address)
+	if m.Statement != "" {
+		fmt.Fprintf(&b, "%s\n", m.Statement)
+	}
+	fmt.Fprintf(&b, "\nURI: %s\nVersion: %s\nChain ID: %s\nNonce: %s", m.URI, m.Version, m.ChainID, m.Nonce)
+	if m.IssuedAt != "" {
+		fmt.Fprintf(&b, "\nIssued At: %s", m.IssuedAt)
+	}
+	if m.ExpirationTime != "" {
+		fmt.Fprintf(&b, "\nExpiration Time: %s", m.ExpirationTime)
+	}
+	if m.NotBefore != "" {
+		fmt.Fprintf(&b, "\nNot Before: %s", m.NotBefore)
+	}
+	if m.RequestID != "" {
+		fmt.Fprintf(&b, "\nRequest ID: %s", m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			fmt.Fprintf(&b, "\n- %s", resource)
+		}
+	}
+	return b.String()
+}
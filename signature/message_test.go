@@ -0,0 +1,137 @@
+package signature
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMessage(t *testing.T) {
+	evm, err := NewChallenge(ChallengeOptions{
+		Address:        wallet,
+		Domain:         tDomain,
+		URI:            tUri,
+		Nonce:          nonce,
+		Statement:      tStatement,
+		Resources:      []string{"https://seedao.xyz/terms"},
+		IssuedAt:       time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		ExpirationTime: time.Date(2024, 6, 1, 12, 1, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("NewChallenge() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    *Message
+		wantErr bool
+	}{
+		{
+			name: "siwe",
+			text: evm,
+			want: &Message{
+				Domain:         tDomain,
+				Blockchain:     "Ethereum",
+				Address:        wallet,
+				Statement:      tStatement,
+				URI:            tUri,
+				Version:        "1",
+				ChainID:        "1",
+				Nonce:          nonce,
+				IssuedAt:       "2024-06-01T12:00:00Z",
+				ExpirationTime: "2024-06-01T12:01:00Z",
+				Resources:      []string{"https://seedao.xyz/terms"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "siws without statement",
+			text: "app.seedao.xyz wants you to sign in with your Solana account:\n" +
+				"7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV\n\n\n" +
+				"URI: https://app.seedao.xyz\n" +
+				"Version: 1\n" +
+				"Chain ID: mainnet\n" +
+				"Nonce: oNCEHm5jzQU2WvuBB\n" +
+				"Issued At: 2024-06-01T12:00:00.000Z\n" +
+				"Request ID: request-1",
+			want: &Message{
+				Domain:     tDomain,
+				Blockchain: "Solana",
+				Address:    "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV",
+				URI:        tUri,
+				Version:    "1",
+				ChainID:    "mainnet",
+				Nonce:      nonce,
+				IssuedAt:   "2024-06-01T12:00:00.000Z",
+				RequestID:  "request-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "siws without issued at",
+			text: "app.seedao.xyz wants you to sign in with your Solana account:\n" +
+				"7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV\n\n\n" +
+				"URI: https://app.seedao.xyz\n" +
+				"Version: 1\n" +
+				"Chain ID: mainnet\n" +
+				"Nonce: oNCEHm5jzQU2WvuBB\n" +
+				"Expiration Time: 2024-06-01T12:01:00.000Z",
+			want: &Message{
+				Domain:         tDomain,
+				Blockchain:     "Solana",
+				Address:        "7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV",
+				URI:            tUri,
+				Version:        "1",
+				ChainID:        "mainnet",
+				Nonce:          nonce,
+				ExpirationTime: "2024-06-01T12:01:00.000Z",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "siwe without issued at",
+			text:    strings.Replace(evm, "\nIssued At: 2024-06-01T12:00:00Z", "", 1),
+			wantErr: true,
+		},
+		{
+			name:    "invalid header",
+			text:    "app.seedao.xyz wants you to sign in:\n" + wallet + "\n\n\nURI: https://app.seedao.xyz",
+			wantErr: true,
+		},
+		{
+			name: "missing nonce",
+			text: "app.seedao.xyz wants you to sign in with your Solana account:\n" +
+				"7EcDhSYGxXyscszYEp35KHN8vvw3svAuLKTzXwCFLtV\n\n\n" +
+				"URI: https://app.seedao.xyz\n" +
+				"Version: 1\n" +
+				"Chain ID: mainnet\n" +
+				"Issued At: 2024-06-01T12:00:00.000Z",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected line",
+			text:    evm + "\nfoo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMessage(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMessage() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMessage() got = %+v, want = %+v", got, tt.want)
+			}
+			if got.String() != tt.text {
+				t.Errorf("String() got = %q, want = %q", got.String(), tt.text)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package signature
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fields of the SIWE message checked by `MessagePolicy`, they are reported by `PolicyError`
+const (
+	FieldURI            = "uri"
+	FieldChainID        = "chainId"
+	FieldVersion        = "version"
+	FieldStatement      = "statement"
+	FieldIssuedAt       = "issuedAt"
+	FieldExpirationTime = "expirationTime"
+	FieldNotBefore      = "notBefore"
+)
+
+// PolicyError is returned when a field of the SIWE message violates the `MessagePolicy`
+type PolicyError struct {
+	Field string // one of the `Field*` constants
+	Msg   string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Msg)
+}
+
+// MessagePolicy is what a SIWE message must satisfy besides the domain, nonce and signature.
+// The `version` must always be "1", the `uri` must use an allowed scheme and its host must be the `domain`
+type MessagePolicy struct {
+	// Schemes are the allowed schemes of the `uri`, "https" if empty. "http" is always allowed for localhost
+	Schemes []string
+	// ChainIDs are the accepted chain IDs of EVM wallets, any chain is accepted if empty
+	ChainIDs []int
+	// NonEVMChainIDs are the accepted chain IDs of other blockchains, keyed by the blockchain name in the message,
+	// e.g. `{"Solana": {"mainnet"}}`. Any chain of a blockchain is accepted if it has no entry
+	NonEVMChainIDs map[string][]string
+	// Statement is the statement shown to the user, it's not checked if empty
+	Statement string
+	// MaxAge is how long a message is accepted after `issuedAt`, it's not checked if 0.
+	// It's not checked either for a Sign-In-With-Solana message without `issuedAt`, which is optional there
+	MaxAge time.Duration
+	// ClockSkew is how far `issuedAt` can be in the future, because the clocks of the wallet and the server differ
+	ClockSkew time.Duration
+	// Now returns the time at which verifiers check the message, `time.Now` if it's nil
+	Now func() time.Time
+}
+
+// DefaultMessagePolicy is used when no policy is given
+var DefaultMessagePolicy = MessagePolicy{
+	Schemes:   []string{"https"},
+	MaxAge:    10 * time.Minute,
+	ClockSkew: time.Minute,
+}
+
+// Check parses the Sign-In-With-X `message` and checks it at `now`, a violation is returned as `*PolicyError`
+func (p *MessagePolicy) Check(message string, now time.Time) error {
+	m, err := ParseMessage(message)
+	if err != nil {
+		return err
+	}
+	return p.check(m, now)
+}
+
+// now returns the time at which verifiers check the message
+func (p *MessagePolicy) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+func (p *MessagePolicy) check(m *Message, now time.Time) error {
+	uri, err := url.Parse(m.URI)
+	if err != nil {
+		return &PolicyError{Field: FieldURI, Msg: err.Error()}
+	}
+	if !p.schemeAllowed(uri.Scheme, uri.Hostname()) {
+		return &PolicyError{Field: FieldURI, Msg: fmt.Sprintf("scheme %q not allowed", uri.Scheme)}
+	}
+	if !strings.EqualFold(uri.Host, m.Domain) {
+		return &PolicyError{Field: FieldURI, Msg: fmt.Sprintf("host %q not match domain %q", uri.Host, m.Domain)}
+	}
+
+	if !p.chainAccepted(m.Blockchain, m.ChainID) {
+		return &PolicyError{Field: FieldChainID, Msg: fmt.Sprintf("chain %s not accepted", m.ChainID)}
+	}
+
+	if m.Version != "1" {
+		return &PolicyError{Field: FieldVersion, Msg: fmt.Sprintf("version %q not supported", m.Version)}
+	}
+
+	if p.Statement != "" && m.Statement != p.Statement {
+		return &PolicyError{Field: FieldStatement, Msg: "statement not match"}
+	}
+
+	if m.IssuedAt != "" {
+		issuedAt, err := time.Parse(time.RFC3339, m.IssuedAt)
+		if err != nil {
+			return &PolicyError{Field: FieldIssuedAt, Msg: err.Error()}
+		}
+		if issuedAt.After(now.Add(p.ClockSkew)) {
+			return &PolicyError{Field: FieldIssuedAt, Msg: "issued in the future"}
+		}
+		if p.MaxAge > 0 && now.Sub(issuedAt) > p.MaxAge {
+			return &PolicyError{Field: FieldIssuedAt, Msg: fmt.Sprintf("issued more than %s ago", p.MaxAge)}
+		}
+	}
+
+	if m.ExpirationTime != "" {
+		expirationTime, err := time.Parse(time.RFC3339, m.ExpirationTime)
+		if err != nil {
+			return &PolicyError{Field: FieldExpirationTime, Msg: err.Error()}
+		}
+		if now.After(expirationTime) {
+			return &PolicyError{Field: FieldExpirationTime, Msg: "message expired"}
+		}
+	}
+	if m.NotBefore != "" {
+		notBefore, err := time.Parse(time.RFC3339, m.NotBefore)
+		if err != nil {
+			return &PolicyError{Field: FieldNotBefore, Msg: err.Error()}
+		}
+		if now.Before(notBefore) {
+			return &PolicyError{Field: FieldNotBefore, Msg: "message not yet valid"}
+		}
+	}
+
+	return nil
+}
+
+func (p *MessagePolicy) chainAccepted(blockchain, chainID string) bool {
+	if blockchain == "Ethereum" {
+		if len(p.ChainIDs) == 0 {
+			return true
+		}
+		id, err := strconv.Atoi(chainID)
+		return err == nil && containsInt(p.ChainIDs, id)
+	}
+
+	chainIDs, ok := p.NonEVMChainIDs[blockchain]
+	if !ok {
+		return true
+	}
+	for _, id := range chainIDs {
+		if id == chainID {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *MessagePolicy) schemeAllowed(scheme, host string) bool {
+	schemes := p.Schemes
+	if len(schemes) == 0 {
+		schemes = DefaultMessagePolicy.Schemes
+	}
+	for _, s := range schemes {
+		if strings.EqualFold(s, scheme) {
+			return true
+		}
+	}
+	return strings.EqualFold(scheme, "http") && isLocalhost(host)
+}
+
+func isLocalhost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package signature
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spruceid/siwe-go"
+)
+
+func TestMessagePolicy_Check(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	policy := &MessagePolicy{
+		ChainIDs:  []int{1, 10},
+		Statement: tStatement,
+		MaxAge:    5 * time.Minute,
+		ClockSkew: 30 * time.Second,
+	}
+
+	type args struct {
+		domain  string
+		uri     string
+		options map[string]interface{}
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantField string
+		wantErr   bool
+	}{
+		{name: "ok", args: args{options: map[string]interface{}{}}, wantErr: false},
+		{name: "http localhost", args: args{domain: "localhost:3000", uri: "http://localhost:3000", options: map[string]interface{}{}}, wantErr: false},
+		{name: "http not allowed", args: args{uri: "http://app.seedao.xyz", options: map[string]interface{}{}}, wantField: FieldURI, wantErr: true},
+		{name: "uri host not match domain", args: args{uri: "https://evil.xyz", options: map[string]interface{}{}}, wantField: FieldURI, wantErr: true},
+		{name: "chain not accepted", args: args{options: map[string]interface{}{"chainId": 137}}, wantField: FieldChainID, wantErr: true},
+		{name: "statement not match", args: args{options: map[string]interface{}{"statement": "Welcome!"}}, wantField: FieldStatement, wantErr: true},
+		{name: "issued too long ago", args: args{options: map[string]interface{}{"issuedAt": now.Add(-6 * time.Minute).Format(time.RFC3339)}}, wantField: FieldIssuedAt, wantErr: true},
+		{name: "issued in the future", args: args{options: map[string]interface{}{"issuedAt": now.Add(time.Minute).Format(time.RFC3339)}}, wantField: FieldIssuedAt, wantErr: true},
+		{name: "expired", args: args{options: map[string]interface{}{"expirationTime": now.Add(-time.Second).Format(time.RFC3339)}}, wantField: FieldExpirationTime, wantErr: true},
+		{name: "not yet valid", args: args{options: map[string]interface{}{"notBefore": now.Add(time.Minute).Format(time.RFC3339)}}, wantField: FieldNotBefore, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain, uri := tDomain, tUri
+			if tt.args.domain != "" {
+				domain = tt.args.domain
+			}
+			if tt.args.uri != "" {
+				uri = tt.args.uri
+			}
+			options := map[string]interface{}{
+				"statement": tStatement,
+				"chainId":   tChainId,
+				"version":   tVersion,
+				"issuedAt":  now.Format(time.RFC3339),
+			}
+			for k, v := range tt.args.options {
+				options[k] = v
+			}
+			m, err := siwe.InitMessage(domain, wallet, uri, nonce, options)
+			if err != nil {
+				t.Fatalf("InitMessage() error = %v", err)
+			}
+
+			err = policy.Check(m.String(), now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			var policyErr *PolicyError
+			if err != nil && (!errors.As(err, &policyErr) || policyErr.Field != tt.wantField) {
+				t.Errorf("Check() error = %v, wantField = %v", err, tt.wantField)
+			}
+		})
+	}
+}
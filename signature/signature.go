@@ -1,13 +1,13 @@
 package signature
 
 import (
-	"errors"
+	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/spruceid/siwe-go"
 )
 
 // flowing constants are only used in `Sign` function
@@ -27,20 +27,24 @@ func Sign(nonce string, signatureLifetime time.Duration, privateKey string) (mes
 	if err != nil {
 		return
 	}
-	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	return sign(crypto.PubkeyToAddress(key.PublicKey).Hex(), nonce, signatureLifetime, key)
+}
 
-	options := map[string]interface{}{
-		"statement":      tStatement,
-		"chainId":        tChainId,
-		"version":        tVersion,
-		"issuedAt":       time.Now().UTC().Format(time.RFC3339),
-		"expirationTime": time.Now().UTC().Add(signatureLifetime).Format(time.RFC3339),
-	}
-	m, err := siwe.InitMessage(tDomain, address, tUri, nonce, options)
+// sign signs a SIWE message of `address` by `key`, they are different when `address` is a contract wallet
+func sign(address, nonce string, signatureLifetime time.Duration, key *ecdsa.PrivateKey) (message, signature string, err error) {
+	message, err = NewChallenge(ChallengeOptions{
+		Address:        address,
+		Domain:         tDomain,
+		URI:            tUri,
+		ChainID:        tChainId,
+		Nonce:          nonce,
+		Statement:      tStatement,
+		IssuedAt:       time.Now(),
+		ExpirationTime: time.Now().Add(signatureLifetime),
+	})
 	if err != nil {
 		return
 	}
-	message = m.String()
 	//fmt.Printf("~~~~message: %s\n", message)
 
 	data := []byte(message)
@@ -58,20 +62,9 @@ func Sign(nonce string, signatureLifetime time.Duration, privateKey string) (mes
 	return
 }
 
+// Verify verifies the SIWE `message` is signed by the EOA `wallet`, the message is checked by `DefaultMessagePolicy`.
+// See `Verifier` to accept contract wallets or to use another policy
 func Verify(wallet, domain, nonce, message, signature string) error {
-	m, err := siwe.ParseMessage(message)
-	if err != nil {
-		return err
-	}
-
-	publicKey, err := m.Verify(signature, &domain, &nonce, nil)
-	if err != nil {
-		return err
-	}
-
-	if crypto.PubkeyToAddress(*publicKey).Hex() != wallet {
-		return errors.New("signer not match")
-	}
-
-	return nil
+	_, err := (&Verifier{}).Verify(context.Background(), wallet, domain, nonce, message, signature)
+	return err
 }
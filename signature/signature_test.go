@@ -3,6 +3,8 @@ package signature
 import (
 	"testing"
 	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
@@ -104,3 +106,40 @@ func TestVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestEcrecover(t *testing.T) {
+	key, err := crypto.HexToECDSA(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := eip191Hash("SeeDAO")
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		v       byte
+		wantErr bool
+	}{
+		{name: "v = 0 or 1", v: sig[64], wantErr: false},
+		{name: "v = 27 or 28", v: sig[64] + 27, wantErr: false},
+		{name: "v = 54 or 55", v: sig[64] + 54, wantErr: true},
+		{name: "v = 2 or 3", v: sig[64] + 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := append([]byte(nil), sig...)
+			s[64] = tt.v
+			got, err := ecrecover(hash, s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ecrecover() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Hex() != wallet {
+				t.Errorf("ecrecover() got = %v, want = %v", got.Hex(), wallet)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package signature
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SolanaVerifier verifies Sign-In-With-Solana signatures, which are ed25519 signatures over the message
+type SolanaVerifier struct {
+	// Policy is what the message must satisfy, `DefaultMessagePolicy` is used if it's nil
+	Policy *MessagePolicy
+}
+
+// Verify verifies the Sign-In-With-Solana `message` is signed by `wallet`, the base58 address of the wallet.
+// The signature is base58 or `0x` prefixed hex
+func (v *SolanaVerifier) Verify(ctx context.Context, wallet, domain, nonce, message, signature string) (*Result, error) {
+	m, err := ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if m.Blockchain != "Solana" {
+		return nil, fmt.Errorf("not a Sign-In-With-Solana message: %s account", m.Blockchain)
+	}
+	if err = checkFields(v.Policy, m, domain, nonce); err != nil {
+		return nil, err
+	}
+	if m.Address != wallet {
+		return nil, errors.New("signer not match")
+	}
+
+	publicKey, err := ParseSolanaAddress(wallet)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := decodeSolanaSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(publicKey, []byte(message), sig) {
+		return nil, errors.New("signer not match")
+	}
+
+	return &Result{Wallet: wallet, SignerType: SignerEOA}, nil
+}
+
+// ParseSolanaAddress parses a base58 Solana address, it's the 32 bytes ed25519 public key
+func ParseSolanaAddress(address string) (ed25519.PublicKey, error) {
+	b, err := base58Decode(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Solana address: %w", err)
+	}
+	if len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Solana address: %d bytes", len(b))
+	}
+	return b, nil
+}
+
+// SolanaAddress returns the base58 Solana address of `publicKey`
+func SolanaAddress(publicKey ed25519.PublicKey) string {
+	return base58Encode(publicKey)
+}
+
+// IsSolanaAddress reports whether `address` is a valid base58 Solana address
+func IsSolanaAddress(address string) bool {
+	_, err := ParseSolanaAddress(address)
+	return err == nil
+}
+
+func decodeSolanaSignature(signature string) ([]byte, error) {
+	var sig []byte
+	var err error
+	if strings.HasPrefix(signature, "0x") {
+		sig, err = hexutil.Decode(signature)
+	} else {
+		sig, err = base58Decode(signature)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	return sig, nil
+}
@@ -0,0 +1,124 @@
+package signature
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// signSolana signs a Sign-In-With-Solana message of `key`
+func signSolana(key ed25519.PrivateKey, chainID string, signatureLifetime time.Duration) (message, signature string) {
+	return signSolanaMessage(key, newSolanaMessage(key, chainID, signatureLifetime))
+}
+
+// newSolanaMessage returns a Sign-In-With-Solana message of `key`, it's issued now
+func newSolanaMessage(key ed25519.PrivateKey, chainID string, signatureLifetime time.Duration) *Message {
+	return &Message{
+		Domain:         tDomain,
+		Blockchain:     "Solana",
+		Address:        base58Encode(key.Public().(ed25519.PublicKey)),
+		Statement:      tStatement,
+		URI:            tUri,
+		Version:        "1",
+		ChainID:        chainID,
+		Nonce:          nonce,
+		IssuedAt:       time.Now().UTC().Format(time.RFC3339),
+		ExpirationTime: time.Now().UTC().Add(signatureLifetime).Format(time.RFC3339),
+	}
+}
+
+// signSolanaMessage signs the Sign-In-With-Solana message `m` by `key`
+func signSolanaMessage(key ed25519.PrivateKey, m *Message) (message, signature string) {
+	message = m.String()
+	return message, base58Encode(ed25519.Sign(key, []byte(message)))
+}
+
+func TestSolanaVerifier_Verify(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	address := base58Encode(key.Public().(ed25519.PublicKey))
+	message, signature := signSolana(key, "mainnet", signatureLifetime)
+	_, otherSignature := signSolana(otherKey, "mainnet", signatureLifetime)
+	expiredMessage, expiredSignature := signSolana(key, "mainnet", -signatureLifetime)
+	devnetMessage, devnetSignature := signSolana(key, "devnet", signatureLifetime)
+	evmMessage, evmSignature, _ := Sign(nonce, signatureLifetime, privateKey)
+	noIssuedAt := newSolanaMessage(key, "mainnet", signatureLifetime)
+	noIssuedAt.IssuedAt = ""
+	noIssuedAtMessage, noIssuedAtSignature := signSolanaMessage(key, noIssuedAt)
+	mainnetOnly := &MessagePolicy{NonEVMChainIDs: map[string][]string{"Solana": {"mainnet"}}}
+
+	tests := []struct {
+		name      string
+		policy    *MessagePolicy
+		wallet    string
+		message   string
+		signature string
+		wantErr   bool
+	}{
+		{name: "ok", wallet: address, message: message, signature: signature, wantErr: false},
+		{name: "hex signature", wallet: address, message: message, signature: hexutil.Encode(base58MustDecode(signature)), wantErr: false},
+		{name: "signed by other", wallet: address, message: message, signature: otherSignature, wantErr: true},
+		{name: "wallet not match", wallet: base58Encode(otherKey.Public().(ed25519.PublicKey)), message: message, signature: signature, wantErr: true},
+		{name: "expired", wallet: address, message: expiredMessage, signature: expiredSignature, wantErr: true},
+		{name: "no issued at", wallet: address, message: noIssuedAtMessage, signature: noIssuedAtSignature, wantErr: false},
+		{name: "chain accepted", policy: mainnetOnly, wallet: address, message: message, signature: signature, wantErr: false},
+		{name: "chain not accepted", policy: mainnetOnly, wallet: address, message: devnetMessage, signature: devnetSignature, wantErr: true},
+		{name: "siwe message", wallet: wallet, message: evmMessage, signature: evmSignature, wantErr: true},
+		{name: "invalid address", wallet: "0OIl", message: message, signature: signature, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&SolanaVerifier{Policy: tt.policy}).Verify(context.Background(), tt.wallet, tDomain, nonce, tt.message, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Wallet != tt.wallet {
+				t.Errorf("Verify() wallet = %v, want = %v", got.Wallet, tt.wallet)
+			}
+		})
+	}
+}
+
+func base58MustDecode(s string) []byte {
+	b, err := base58Decode(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		name    string
+		hex     string
+		base58  string
+		wantErr bool
+	}{
+		{name: "empty bytes", hex: "0x", base58: "", wantErr: true},
+		{name: "leading zeros", hex: "0x0000287fb4cd", base58: "11233QC4", wantErr: false},
+		{name: "hello world", hex: "0x68656c6c6f20776f726c64", base58: "StV1DL6CwTryKyV", wantErr: false},
+		{name: "invalid character", base58: "0OIl", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := base58Decode(tt.base58)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("base58Decode() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if hexutil.Encode(got) != tt.hex {
+				t.Errorf("base58Decode() got = %v, want = %v", hexutil.Encode(got), tt.hex)
+			}
+			if base58Encode(got) != tt.base58 {
+				t.Errorf("base58Encode() got = %v, want = %v", base58Encode(got), tt.base58)
+			}
+		})
+	}
+}
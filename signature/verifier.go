@@ -0,0 +1,171 @@
+package signature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spruceid/siwe-go"
+)
+
+// SignerType tells which kind of account signed the message
+type SignerType string
+
+const (
+	SignerEOA      SignerType = "eoa"      // an account controlled by a key pair, e.g. an EVM account of which the signature is recovered by ecrecover
+	SignerContract SignerType = "contract" // a contract wallet, the signature is checked by EIP-1271 `isValidSignature`, or EIP-6492 if it's not deployed yet
+	SignerPasskey  SignerType = "passkey"  // a passkey of JoyID, the signature is a WebAuthn assertion
+)
+
+// WalletVerifier verifies the SIWE `message` is signed by `wallet`, each kind of wallet has its own verifier
+type WalletVerifier interface {
+	Verify(ctx context.Context, wallet, domain, nonce, message, signature string) (*Result, error)
+}
+
+// Result is the result of a successful verification
+type Result struct {
+	Wallet     string
+	SignerType SignerType
+}
+
+// Verifier verifies SIWE signatures of EVM wallets
+type Verifier struct {
+	// Caller is used to verify signatures of contract wallets (e.g. Safe) by EIP-1271,
+	// and signatures of counterfactual wallets (e.g. ERC-4337 accounts not deployed yet) by EIP-6492.
+	// Only signatures of EOA wallets are accepted if it's nil
+	Caller ContractCaller
+	// Policy is what the SIWE message must satisfy, `DefaultMessagePolicy` is used if it's nil
+	Policy *MessagePolicy
+}
+
+// Verify verifies the SIWE `message` is signed by `wallet`, the wallet can be an EOA or a contract wallet
+func (v *Verifier) Verify(ctx context.Context, wallet, domain, nonce, message, signature string) (*Result, error) {
+	m, err := checkMessage(v.Policy, wallet, domain, nonce, message)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := hexutil.Decode(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	hash := eip191Hash(m.String())
+
+	if isEIP6492Signature(sig) {
+		return v.verifyEIP6492(ctx, wallet, m.GetAddress(), hash, sig)
+	}
+
+	if signer, err := ecrecover(hash, sig); err == nil && signer == m.GetAddress() {
+		return &Result{Wallet: wallet, SignerType: SignerEOA}, nil
+	}
+
+	// ecrecover doesn't match, maybe it's a contract wallet
+	if v.Caller == nil {
+		return nil, errors.New("signer not match")
+	}
+	code, err := v.Caller.CodeAt(ctx, m.GetAddress(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("get code of wallet: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, errors.New("signer not match")
+	}
+	if err = isValidSignature(ctx, v.Caller, m.GetAddress(), hash, sig); err != nil {
+		return nil, err
+	}
+	return &Result{Wallet: wallet, SignerType: SignerContract}, nil
+}
+
+// checkMessage parses the SIWE `message`, and checks it by `policy`, then checks its domain, nonce and address.
+// `DefaultMessagePolicy` is used if `policy` is nil
+func checkMessage(policy *MessagePolicy, wallet, domain, nonce, message string) (*siwe.Message, error) {
+	m, err := siwe.ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := ParseMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkFields(policy, fields, domain, nonce); err != nil {
+		return nil, err
+	}
+	if m.GetAddress().Hex() != wallet {
+		return nil, errors.New("signer not match")
+	}
+	return m, nil
+}
+
+// checkFields checks the message by `policy`, then checks its domain and nonce.
+// `DefaultMessagePolicy` is used if `policy` is nil
+func checkFields(policy *MessagePolicy, m *Message, domain, nonce string) error {
+	if policy == nil {
+		policy = &DefaultMessagePolicy
+	}
+	if err := policy.check(m, policy.now()); err != nil {
+		return err
+	}
+	if m.Domain != domain {
+		return errors.New("message domain doesn't match")
+	}
+	if m.Nonce != nonce {
+		return errors.New("message nonce doesn't match")
+	}
+	return nil
+}
+
+// verifyEIP6492 verifies a signature wrapped by EIP-6492, the wallet may be not deployed yet
+func (v *Verifier) verifyEIP6492(ctx context.Context, wallet string, address common.Address, hash common.Hash, sig []byte) (*Result, error) {
+	if v.Caller == nil {
+		return nil, errors.New("contract caller is required to verify EIP-6492 signature")
+	}
+	unwrapped, err := parseEIP6492Signature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := v.Caller.CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get code of wallet: %w", err)
+	}
+	if len(code) > 0 {
+		// already deployed, the factory is not needed any more
+		err = isValidSignature(ctx, v.Caller, address, hash, unwrapped.Signature)
+	} else {
+		err = isValidSignatureOffchain(ctx, v.Caller, address, hash, sig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Wallet: wallet, SignerType: SignerContract}, nil
+}
+
+// eip191Hash hashes `message` as `personal_sign` does
+func eip191Hash(message string) common.Hash {
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+}
+
+// ecrecover recovers the address which signed `hash`, `v` of `sig` can be 0, 1, 27 or 28
+func ecrecover(hash common.Hash, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, errors.New("invalid signature length")
+	}
+	s := make([]byte, crypto.SignatureLength)
+	copy(s, sig)
+	switch s[64] {
+	case 0, 1:
+	case 27, 28:
+		s[64] -= 27
+	default:
+		return common.Address{}, errors.New("invalid signature recovery byte")
+	}
+
+	publicKey, err := crypto.SigToPub(hash.Bytes(), s)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
@@ -5,6 +5,9 @@ type WalletName string
 const (
 	WalletNameMetamask WalletName = "metamask"
 	WalletNameJoyid    WalletName = "joyid"
+	WalletNameSolana   WalletName = "solana"  // any Solana wallet which supports Sign-In-With-Solana, e.g. Phantom
+	WalletNameBitcoin  WalletName = "bitcoin" // any Bitcoin wallet which signs by `signmessage` or BIP-322, e.g. Unisat and Xverse
+	WalletNameCosmos   WalletName = "cosmos"  // any Cosmos wallet which signs by ADR-036, e.g. Keplr
 )
 
 type SeeLogin struct {
@@ -17,7 +20,7 @@ type SeeLogin struct {
 
 type (
 	SeeAuth struct {
-		Wallet     string     `json:"wallet"`
+		Wallet     string     `json:"wallet"` // an address, or a CAIP-10 account ID, e.g. `eip155:1:0x…`
 		WalletName WalletName `json:"walletName"`
 		Signature  *Signature `json:"signature"`
 		Proof      *Proof     `json:"proof"`